@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/azdren/verman/internal/languages"
+	"github.com/azdren/verman/internal/version"
+	"github.com/spf13/cobra"
+)
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Manage version aliases",
+	Long: `Give versions your own names. Aliases are stored per language in
+config.json and work wherever a version does: install, use, and version
+files such as .java-version. Repointing an alias updates every script and
+project that names it.
+
+Examples:
+  verman alias set java work 17-amzn   # "work" means 17-amzn
+  verman use java work
+  verman alias set node ci "^20.10"    # Aliases may name ranges or lts
+  verman alias list                    # Show all aliases
+  verman alias rm java work`,
+}
+
+var aliasSetCmd = &cobra.Command{
+	Use:   "set <language> <name> <version>",
+	Short: "Create or repoint an alias",
+	Args:  cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		langName, name, ver := args[0], args[1], args[2]
+
+		mgr := version.NewManager(cfg)
+		if err := mgr.SetAlias(langName, name, ver); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s %s -> %s\n", langName, name, ver)
+
+		resolved, err := mgr.ResolveInstalled(langName, name)
+		if err == nil {
+			_, err = os.Stat(cfg.GetVersionPath(langName, resolved))
+		}
+		if err != nil {
+			fmt.Printf("(%s %s is not installed yet: verman install %s %s)\n", langName, ver, langName, name)
+		}
+	},
+}
+
+var aliasRmCmd = &cobra.Command{
+	Use:     "rm <language> <name>",
+	Aliases: []string{"remove", "unset"},
+	Short:   "Remove an alias",
+	Args:    cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := version.NewManager(cfg).RemoveAlias(args[0], args[1]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Removed %s alias %s\n", args[0], args[1])
+	},
+}
+
+var aliasListCmd = &cobra.Command{
+	Use:   "list [language]",
+	Short: "List aliases",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		langNames := languages.Names()
+		sort.Strings(langNames)
+		if len(args) == 1 {
+			langNames = []string{args[0]}
+		}
+
+		found := false
+		for _, langName := range langNames {
+			aliases := cfg.Languages[langName].Aliases
+			if len(aliases) == 0 {
+				continue
+			}
+			found = true
+
+			names := make([]string, 0, len(aliases))
+			for name := range aliases {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			fmt.Printf("%s:\n", langName)
+			for _, name := range names {
+				fmt.Printf("  %-12s -> %s\n", name, aliases[name])
+			}
+		}
+
+		if !found {
+			fmt.Println("No aliases defined")
+			fmt.Println("Use 'verman alias set <language> <name> <version>' to create one")
+		}
+	},
+}
+
+func init() {
+	aliasCmd.AddCommand(aliasSetCmd)
+	aliasCmd.AddCommand(aliasRmCmd)
+	aliasCmd.AddCommand(aliasListCmd)
+	rootCmd.AddCommand(aliasCmd)
+}
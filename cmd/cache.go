@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/azdren/verman/internal/version"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the download cache",
+	Long: `Downloaded archives are kept in ~/.verman/cache and reused when the same
+version is installed again, so reinstalls and additional version roots do not
+download anything. The cache is capped at 10 GiB by default (cache_max_size in
+config.json); least recently used downloads are evicted first.
+
+Examples:
+  verman cache list                    # Show cached downloads
+  verman cache clean                   # Remove everything
+  verman cache clean --older-than 30d  # Remove downloads unused for 30 days`,
+}
+
+var cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List cached downloads",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cache := version.NewManager(cfg).DownloadCache()
+		entries, err := cache.Entries()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(entries) == 0 {
+			fmt.Printf("Download cache is empty (%s)\n", cache.Dir)
+			return
+		}
+
+		var total int64
+		seen := make(map[string]bool)
+		fmt.Printf("%-10s  %-16s  %s\n", "SIZE", "LAST USED", "URL")
+		for _, e := range entries {
+			fmt.Printf("%-10s  %-16s  %s\n", version.FormatBytes(e.Size), e.LastUsed.Format("2006-01-02 15:04"), e.URL)
+			if !seen[e.SHA256] {
+				seen[e.SHA256] = true
+				total += e.Size
+			}
+		}
+		fmt.Printf("\n%d downloads, %s total (limit %s) in %s\n",
+			len(entries), version.FormatBytes(total), version.FormatBytes(cache.MaxSize), cache.Dir)
+	},
+}
+
+var cacheCleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove cached downloads",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		olderThanStr, _ := cmd.Flags().GetString("older-than")
+		var olderThan time.Duration
+		if olderThanStr != "" {
+			var err error
+			olderThan, err = parseAge(olderThanStr)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		removed, freed, err := version.NewManager(cfg).DownloadCache().Clean(olderThan)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Removed %d cached downloads, freed %s\n", removed, version.FormatBytes(freed))
+	},
+}
+
+// parseAge parses a duration that may also be given in days ("30d")
+func parseAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid age %q (use e.g. 30d or 12h)", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q (use e.g. 30d or 12h)", s)
+	}
+	return d, nil
+}
+
+func init() {
+	cacheCleanCmd.Flags().String("older-than", "", "Only remove downloads not used within this age (e.g. 30d, 12h)")
+	cacheCmd.AddCommand(cacheListCmd)
+	cacheCmd.AddCommand(cacheCleanCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/azdren/verman/internal/languages"
 	"github.com/azdren/verman/internal/version"
@@ -13,6 +14,10 @@ var currentCmd = &cobra.Command{
 	Short: "Show current active versions",
 	Long: `Show the currently active version for a language, or all languages.
 
+A session version ("verman shell") wins over the nearest project version file,
+which wins over the global version; non-global versions show where they were
+set.
+
 Examples:
   verman current        # Show all current versions
   verman current java   # Show current Java version`,
@@ -29,27 +34,52 @@ Examples:
 }
 
 func showCurrent(mgr *version.Manager, langName string) {
-	current, err := mgr.GetCurrent(langName)
+	sel, err := mgr.Select(langName, workingDir())
 	if err != nil {
 		fmt.Printf("%s: (error: %v)\n", langName, err)
 		return
 	}
-	if current == "" {
+	if sel == nil {
 		fmt.Printf("%s: (none)\n", langName)
 	} else {
-		fmt.Printf("%s: %s\n", langName, current)
+		fmt.Printf("%s: %s%s\n", langName, sel.Version, originSuffix(sel))
 	}
 }
 
 func showAllCurrent(mgr *version.Manager) {
+	dir := workingDir()
 	for _, lang := range languages.All() {
-		current, _ := mgr.GetCurrent(lang.Name())
-		if current != "" {
-			fmt.Printf("%-8s %s\n", lang.Name()+":", current)
+		sel, err := mgr.Select(lang.Name(), dir)
+		if err != nil {
+			fmt.Printf("%-8s (error: %v)\n", lang.Name()+":", err)
+			continue
+		}
+		if sel != nil {
+			fmt.Printf("%-8s %s%s\n", lang.Name()+":", sel.Version, originSuffix(sel))
 		}
 	}
 }
 
+// originSuffix notes where a non-global version was selected
+func originSuffix(sel *version.Selection) string {
+	switch sel.Origin {
+	case version.OriginSession:
+		return fmt.Sprintf(" (session, %s)", sel.Source)
+	case version.OriginProject:
+		return fmt.Sprintf(" (project, %s)", sel.Source)
+	}
+	return ""
+}
+
+// workingDir returns the current directory, or "." if it cannot be read
+func workingDir() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "."
+	}
+	return dir
+}
+
 func init() {
 	rootCmd.AddCommand(currentCmd)
 }
@@ -17,13 +17,17 @@ var detectCmd = &cobra.Command{
 Supported files:
   Java:   .java-version, .sdkmanrc
   Scala:  .scala-version
-  Node:   .nvmrc, .node-version
+  Node:   .nvmrc, .node-version, package.json (engines.node)
   Python: .python-version
   Ruby:   .ruby-version
   Go:     .go-version, go.mod
   Rust:   rust-toolchain.toml, rust-toolchain
   .NET:   global.json
 
+Files may hold a range such as "^20.10" or ">=17 <22", or an alias defined
+with "verman alias set"; --apply switches to the newest installed version
+satisfying it.
+
 Examples:
   verman detect              # Show detected versions
   verman detect --apply      # Detect and switch to those versions
@@ -39,7 +43,8 @@ Examples:
 			os.Exit(1)
 		}
 
-		detected, err := version.DetectAll(cwd)
+		mgr := version.NewManager(cfg)
+		detected, err := mgr.Detect(cwd)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
@@ -64,18 +69,22 @@ Examples:
 		if !quiet {
 			fmt.Println("Detected versions:")
 			for _, d := range detected {
-				fmt.Printf("  %-8s %s (from %s)\n", d.Language+":", d.Version, d.Source)
+				if d.Alias != "" {
+					fmt.Printf("  %-8s %s -> %s (from %s)\n", d.Language+":", d.Alias, d.Version, d.Source)
+				} else {
+					fmt.Printf("  %-8s %s (from %s)\n", d.Language+":", d.Version, d.Source)
+				}
 			}
 		}
 
 		if apply {
-			mgr := version.NewManager(cfg)
 			fmt.Println("\nApplying versions:")
 			for _, d := range detected {
-				if err := mgr.Use(d.Language, d.Version, false); err != nil {
+				ver, err := mgr.Use(d.Language, d.Version, false)
+				if err != nil {
 					fmt.Printf("  %-8s %v\n", d.Language+":", err)
 				} else if !quiet {
-					fmt.Printf("  %-8s switched to %s\n", d.Language+":", d.Version)
+					fmt.Printf("  %-8s switched to %s\n", d.Language+":", ver)
 				}
 			}
 		}
@@ -226,7 +226,7 @@ func checkDependencies() int {
 	mgr := version.NewManager(cfg)
 
 	// Check if JVM tools have Java installed
-	jvmTools := []string{"maven", "gradle", "scala", "scala3", "sbt", "kotlin", "mill"}
+	jvmTools := []string{"maven", "gradle", "scala", "scala3", "sbt", "kotlin", "mill", "ant"}
 	javaVersions, _ := mgr.ListInstalled("java")
 	javaInstalled := len(javaVersions) > 0
 
@@ -1,13 +1,10 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
-	"runtime"
-	"strings"
 
-	"github.com/azdren/verman/internal/languages"
 	"github.com/azdren/verman/internal/version"
 	"github.com/spf13/cobra"
 )
@@ -17,73 +14,80 @@ var envCmd = &cobra.Command{
 	Short: "Print environment variable commands for current shell",
 	Long: `Prints commands to set environment variables for all active language versions.
 
+Versions follow the same precedence as the shims: a session version
+("verman shell"), else the nearest project version file, else the global
+version. PATH gets the shim directory and the versions' directories first;
+entries from an earlier run are replaced, not repeated.
+
 Run this after installing a new version to refresh your current terminal:
   PowerShell:  verman env | Invoke-Expression
-  CMD:         Not yet supported
-
-Or copy and paste the output commands manually.`,
+  bash/zsh:    eval "$(verman env)"
+  fish:        verman env --shell fish | source
+  Nushell:     verman env --shell nu | from nuon | load-env
+  Elvish:      eval (verman env --shell elvish | slurp)
+  CMD:         verman env --shell cmd > "%TEMP%\verman-env.cmd" && call "%TEMP%\verman-env.cmd"
+               (batch-file code, so it has to be run from a file)
+
+--unset prints the commands that take verman's variables and PATH entries
+out again; --json prints the variables as a JSON object for other tools.
+
+The shell is detected from $SHELL (PowerShell on Windows) unless --shell is
+given. Or copy and paste the output commands manually.`,
+	Args: cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		mgr := version.NewManager(cfg)
-		home, _ := os.UserHomeDir()
-
-		// Get all registered languages
-		allLangs := languages.Names()
-
-		var pathAdditions []string
-
-		for _, langName := range allLangs {
-			lang, ok := languages.Get(langName)
-			if !ok {
-				continue
-			}
-
-			// Check if this language has a current version
-			currentVersion, err := mgr.GetCurrent(langName)
-			if err != nil || currentVersion == "" {
-				continue
+		shellName, _ := cmd.Flags().GetString("shell")
+
+		sh := version.DetectShell()
+		if shellName != "" {
+			var err error
+			if sh, err = version.ParseShell(shellName); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
 			}
+		}
 
-			currentPath := cfg.GetCurrentPath(langName)
-
-			// Output environment variable settings (like JAVA_HOME)
-			for envVar, relPath := range lang.EnvVars() {
-				fullPath := currentPath
-				if relPath != "." {
-					fullPath = filepath.Join(currentPath, relPath)
-				}
-
-				if runtime.GOOS == "windows" {
-					fmt.Printf("$env:%s = \"%s\"\n", envVar, fullPath)
-				} else {
-					fmt.Printf("export %s=\"%s\"\n", envVar, fullPath)
-				}
-			}
+		unset, _ := cmd.Flags().GetBool("unset")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
 
-			// Collect PATH additions for this language
-			for _, relDir := range lang.PathDirs() {
-				binPath := currentPath
-				if relDir != "." {
-					binPath = filepath.Join(currentPath, relDir)
-				}
-				pathAdditions = append(pathAdditions, binPath)
+		mgr := version.NewManager(cfg)
+		var exports []version.EnvExport
+		if unset {
+			exports = mgr.UnsetEnv(os.Environ())
+		} else {
+			var errs []error
+			exports, errs = mgr.DirEnv(workingDir(), os.Environ())
+			for _, err := range errs {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 			}
 		}
 
-		// Add verman bin directory
-		vermanBin := filepath.Join(home, ".verman", "bin")
-		pathAdditions = append([]string{vermanBin}, pathAdditions...)
-
-		// Output PATH update
-		if len(pathAdditions) > 0 {
-			if runtime.GOOS == "windows" {
-				fmt.Printf("$env:PATH = \"%s;\" + $env:PATH\n", strings.Join(pathAdditions, ";"))
-			} else {
-				fmt.Printf("export PATH=\"%s:$PATH\"\n", strings.Join(pathAdditions, ":"))
-			}
+		if jsonOutput {
+			printEnvJSON(exports)
+			return
 		}
+		fmt.Print(sh.Output(exports))
 	},
 }
 
+// printEnvJSON prints exports as one JSON object, null marking variables
+// to remove
+func printEnvJSON(exports []version.EnvExport) {
+	values := make(map[string]*string, len(exports))
+	for _, e := range exports {
+		if e.Value == "" {
+			values[e.Name] = nil
+		} else {
+			value := e.Value
+			values[e.Name] = &value
+		}
+	}
+	data, _ := json.MarshalIndent(values, "", "  ")
+	fmt.Println(string(data))
+}
+
 func init() {
+	envCmd.Flags().Bool("unset", false, "Print commands that remove verman's variables and PATH entries")
+	envCmd.Flags().Bool("json", false, "Output the variables as a JSON object (null = remove)")
+	envCmd.Flags().String("shell", "", "Shell to generate code for (pwsh, cmd, bash, zsh, fish, nu, elvish); detected by default")
 	rootCmd.AddCommand(envCmd)
 }
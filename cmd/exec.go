@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/azdren/verman/internal/version"
+	"github.com/spf13/cobra"
+)
+
+var execCmd = &cobra.Command{
+	Use:   "exec <language>@<version>... -- <command> [args...]",
+	Short: "Run a command with specific versions without switching",
+	Long: `Run a command with the given installed versions on PATH and their
+environment variables (JAVA_HOME, ...) set, without changing the current
+version. Other shells, IDEs and config.json are unaffected.
+
+Versions can be exact install names, partial versions ("17" picks the newest
+installed 17.x), ranges or aliases. Dependencies that are not named (Java for
+Maven) use their current version. The command's exit code is passed through.
+
+Examples:
+  verman exec java@17 -- java -version
+  verman exec java@17 maven@3.9 -- mvn verify
+  verman exec java@21-amzn gradle@8 -- ./gradlew build
+  verman exec node@^20 -- npm test`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		specs, command := args, []string(nil)
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			specs, command = args[:dash], args[dash:]
+		} else {
+			// The command starts after "--" (kept in args once flag parsing
+			// stops) or at the first argument that is not lang@version
+			for i, arg := range args {
+				if arg == "--" {
+					specs, command = args[:i], args[i+1:]
+					break
+				}
+				if !strings.Contains(arg, "@") {
+					specs, command = args[:i], args[i:]
+					break
+				}
+			}
+		}
+		if len(specs) == 0 || len(command) == 0 {
+			fmt.Fprintln(os.Stderr, "Usage: verman exec <language>@<version>... -- <command> [args...]")
+			os.Exit(1)
+		}
+
+		var tools []version.ToolVersion
+		for _, spec := range specs {
+			tool, err := version.ParseToolVersion(spec)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			// Smart routing: "scala@3.x" -> "scala3"
+			if tool.Language == "scala" && strings.HasPrefix(strings.TrimLeft(tool.Version, "^~<>= "), "3") {
+				tool.Language = "scala3"
+			}
+			tools = append(tools, tool)
+		}
+
+		mgr := version.NewManager(cfg)
+		env, err := mgr.ExecEnv(tools, os.Environ())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		code, err := version.RunCommand(command, env)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", command[0], err)
+		}
+		os.Exit(code)
+	},
+}
+
+func init() {
+	// Flags after the command belong to it ("verman exec java@17 java -version")
+	execCmd.Flags().SetInterspersed(false)
+	rootCmd.AddCommand(execCmd)
+}
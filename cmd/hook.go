@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/azdren/verman/internal/version"
+	"github.com/spf13/cobra"
+)
+
+// hookCmd is called by the cd hook "verman init --hook" installs
+var hookCmd = &cobra.Command{
+	Use:    "hook <shell>",
+	Short:  "Print environment updates for the current directory (used by shell hooks)",
+	Args:   cobra.ExactArgs(1),
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		sh, err := version.ParseShell(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "verman: %v\n", err)
+			os.Exit(1)
+		}
+
+		mgr := version.NewManager(cfg)
+		exports, errs := mgr.HookEnv(workingDir())
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "verman: %v\n", err)
+		}
+		fmt.Print(sh.Output(exports))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(hookCmd)
+}
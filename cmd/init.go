@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/azdren/verman/internal/version"
 	"github.com/spf13/cobra"
@@ -17,24 +18,52 @@ var initCmd = &cobra.Command{
 Supported shells:
   powershell  - PowerShell (default on Windows)
   cmd         - Windows Command Prompt
+  bash, zsh   - Put the shims on PATH, set JAVA_HOME & co. and load completion
+  fish        - Same, as a fish config snippet
+  nu          - Nushell autoload module
+  elvish      - Elvish rc.elv snippet
+
+On Linux and macOS the shell is detected from $SHELL. --hook adds a cd
+hook (bash: prompt hook) that points JAVA_HOME & co. at the versions the
+project's version files pick; the shims follow the project without it.
 
 Examples:
-  verman init                    # Show PowerShell init script
+  verman init                    # Show init script for the current shell
   verman init powershell         # PowerShell integration
   verman init cmd                # CMD batch script
+  eval "$(verman init bash)"     # Try bash integration in this shell
+  verman init zsh --hook         # zsh integration with the cd hook
+  verman init fish | source      # Try fish integration in this shell
+  verman init nu --install       # Save the Nushell autoload module
   verman init --install          # Install to profile automatically`,
 	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		shell := "powershell"
+		sh := version.DetectShell()
 		if len(args) > 0 {
-			shell = args[0]
+			var err error
+			if sh, err = version.ParseShell(args[0]); err != nil {
+				fmt.Fprintf(os.Stderr, "Unknown shell: %s\n", args[0])
+				fmt.Fprintf(os.Stderr, "Supported: powershell, cmd, bash, zsh, fish, nu, elvish\n")
+				os.Exit(1)
+			}
 		}
 
 		install, _ := cmd.Flags().GetBool("install")
+		hook, _ := cmd.Flags().GetBool("hook")
 
-		switch shell {
-		case "powershell", "pwsh":
-			script := version.GeneratePowerShellInit(cfg)
+		exe, err := version.Executable()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		script, err := version.GenerateInit(cfg, sh, exe, hook)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		switch sh {
+		case version.ShellPwsh:
 			if install {
 				installPowerShellProfile(script)
 			} else {
@@ -44,8 +73,7 @@ Examples:
 				fmt.Println("# Or run: verman init --install")
 			}
 
-		case "cmd":
-			script := version.GenerateCmdInit(cfg)
+		case version.ShellCmd:
 			if install {
 				installCmdScript(script)
 			} else {
@@ -54,14 +82,139 @@ Examples:
 				fmt.Println("REM Or run: verman init cmd --install")
 			}
 
+		case version.ShellNu:
+			if install {
+				installNuModule(script)
+			} else {
+				fmt.Print(script)
+			}
+
 		default:
-			fmt.Fprintf(os.Stderr, "Unknown shell: %s\n", shell)
-			fmt.Fprintf(os.Stderr, "Supported: powershell, cmd\n")
-			os.Exit(1)
+			if install {
+				installShellRC(sh, exe, hook)
+			} else {
+				fmt.Print(script)
+			}
 		}
 	},
 }
 
+// shellRCMarker marks the line "verman init --install" adds to an rc file
+const shellRCMarker = "# verman shell integration"
+
+// installShellRC makes the shell's rc file load "verman init" on startup.
+// bash, zsh and elvish get an eval line appended to ~/.bashrc, ~/.zshrc or
+// rc.elv; fish gets its own file in conf.d.
+func installShellRC(sh version.Shell, exe string, hook bool) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	initArgs := " init " + string(sh)
+	if hook {
+		initArgs += " --hook"
+	}
+
+	var rcPath, line string
+	switch sh {
+	case version.ShellFish:
+		configDir := os.Getenv("XDG_CONFIG_HOME")
+		if configDir == "" {
+			configDir = filepath.Join(home, ".config")
+		}
+		rcPath = filepath.Join(configDir, "fish", "conf.d", "verman.fish")
+		line = sh.Quote(exe) + initArgs + " | source"
+	case version.ShellElvish:
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		rcPath = filepath.Join(configDir, "elvish", "rc.elv")
+		line = fmt.Sprintf("eval (%s%s | slurp)", sh.Quote(exe), initArgs)
+	case version.ShellZsh:
+		dir := os.Getenv("ZDOTDIR")
+		if dir == "" {
+			dir = home
+		}
+		rcPath = filepath.Join(dir, ".zshrc")
+		line = fmt.Sprintf("eval \"$(%s%s)\"", sh.Quote(exe), initArgs)
+	default:
+		rcPath = filepath.Join(home, ".bashrc")
+		line = fmt.Sprintf("eval \"$(%s%s)\"", sh.Quote(exe), initArgs)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(rcPath), 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	// The fish file is verman's own; rewrite it so --hook can be toggled
+	if sh == version.ShellFish {
+		if err := os.WriteFile(rcPath, []byte(shellRCMarker+"\n"+line+"\n"), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Installed to: %s\n", rcPath)
+		fmt.Println("Open a new fish shell for changes to take effect")
+		return
+	}
+
+	existing, _ := os.ReadFile(rcPath)
+	if strings.Contains(string(existing), shellRCMarker) {
+		fmt.Println("Verman integration already installed")
+		fmt.Printf("Profile: %s\n", rcPath)
+		return
+	}
+
+	f, err := os.OpenFile(rcPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening profile: %v\n", err)
+		os.Exit(1)
+	}
+	defer func() { _ = f.Close() }()
+
+	if len(existing) > 0 {
+		if !strings.HasSuffix(string(existing), "\n") {
+			_, _ = f.WriteString("\n")
+		}
+		_, _ = f.WriteString("\n")
+	}
+	_, _ = f.WriteString(shellRCMarker + "\n" + line + "\n")
+
+	fmt.Printf("Installed to: %s\n", rcPath)
+	fmt.Printf("Restart %s or run: source %s\n", sh, rcPath)
+}
+
+// installNuModule saves the Nushell module to the autoload directory. Nushell
+// cannot evaluate "verman init" output at startup, so the module is static
+// and has to be installed again after installing a new language.
+func installNuModule(script string) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		configDir = dir
+	}
+
+	modulePath := filepath.Join(configDir, "nushell", "autoload", "verman.nu")
+	if err := os.MkdirAll(filepath.Dir(modulePath), 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating directory: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(modulePath, []byte(script), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Installed to: %s\n", modulePath)
+	fmt.Println("Open a new Nushell for changes to take effect")
+}
+
 func installPowerShellProfile(script string) {
 	// Get PowerShell profile path
 	home, err := os.UserHomeDir()
@@ -145,5 +298,6 @@ func containsHelper(s, substr string) bool {
 
 func init() {
 	initCmd.Flags().Bool("install", false, "Install to shell profile")
+	initCmd.Flags().Bool("hook", false, "Also switch JAVA_HOME & co. per project on directory change (bash, zsh, fish, nu, elvish)")
 	rootCmd.AddCommand(initCmd)
 }
@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/azdren/verman/internal/languages"
 	"github.com/azdren/verman/internal/sources"
@@ -15,29 +17,66 @@ var installCmd = &cobra.Command{
 	Short: "Install a specific version",
 	Long: `Download and install a specific version of a language runtime.
 
-Version can be partial (e.g., "20" for Node.js will resolve to latest 20.x.x).
+Version can be partial (e.g., "20" for Node.js will resolve to latest 20.x.x)
+or a range in npm/cargo syntax: "^20.10", "~3.3", ">=17 <22", "^18 || ^20".
+The newest version satisfying the range is installed.
+"latest" installs the newest release; "lts" the newest long-term support
+release (Java, Node.js) and "lts/<codename>" the newest of a Node.js LTS line.
+Java versions with a minor or patch part are resolved against the Foojay Disco
+API and install that exact build; a bare major version installs the vendor's
+latest build for it.
+
+--platform downloads the build for another system (e.g., to copy to a machine
+without network access). Such builds cannot run here, so they are saved to the
+current directory instead of being installed.
 
 For Java, you can specify a distribution using SDKMAN-style suffixes:
   - No suffix: Eclipse Temurin (default)
   - -tem or -temurin: Eclipse Temurin
   - -amzn or -corretto: Amazon Corretto
   - -zulu: Azul Zulu
+  - -librca or -liberica: BellSoft Liberica
+  - -ms or -microsoft: Microsoft Build of OpenJDK
+  - -sapmchn or -sapmachine: SapMachine
+  - -graalce: GraalVM Community
+  - -sem or -semeru: IBM Semeru
+  - -oracle: Oracle JDK
 
 Examples:
   verman install java 21           # Temurin (default)
   verman install java 21-tem       # Temurin (explicit)
   verman install java 21-amzn      # Amazon Corretto
   verman install java 21-zulu      # Azul Zulu
+  verman install java 21.0.2       # Exact Temurin build
+  verman install java 21.0.2-amzn  # Exact Corretto build
+  verman install java 21-graalce   # Latest GraalVM Community 21 build
   verman install node 20           # Resolves to latest 20.x.x
   verman install node 20.10.0      # Exact version
-  verman install scala 2.13.x      # Latest 2.13 patch version`,
+  verman install scala 2.13.x      # Latest 2.13 patch version
+  verman install node "^20.10"     # Latest 20.x at or above 20.10
+  verman install java ">=17 <22"   # Latest Temurin build from 17 up to 21
+  verman install java lts          # Newest LTS major (Temurin)
+  verman install node lts/iron     # Latest Node.js 20 (Iron) release
+  verman install gradle latest     # Newest Gradle release
+  verman install java work         # Version the "work" alias points at
+  verman install node 20 --platform linux/arm64   # Save the linux/arm64 archive here`,
 	Args: cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		langName := args[0]
 		ver := args[1]
 
+		// Override target platform for {os}/{arch} placeholders
+		if platform, _ := cmd.Flags().GetString("platform"); platform != "" {
+			p, err := sources.ParsePlatform(platform)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			sources.SetPlatform(p)
+		}
+
 		// Smart routing: "scala 3.x" -> "scala3"
-		if langName == "scala" && len(ver) > 0 && ver[0] == '3' {
+		if langName == "scala" && strings.HasPrefix(strings.TrimLeft(ver, "^~<>= "), "3") {
 			langName = "scala3"
 		}
 
@@ -48,8 +87,15 @@ Examples:
 			os.Exit(1)
 		}
 
-		// Parse distribution suffix if present (e.g., "21-tem" -> "21", "tem")
-		baseVer, dist := sources.ParseVersionAndDistribution(ver)
+		// Resolve partial versions, ranges, channels and user-defined
+		// aliases (verman alias set java work 17-amzn) to a full version
+		mgr := version.NewManager(cfg)
+		resolvedVer, dist, err := mgr.ResolveInstallVersion(langName, ver)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error resolving version: %v\n", err)
+			printOfflineHint(err)
+			os.Exit(1)
+		}
 
 		// Show distribution info for Java
 		if lang.HasDistributions() && dist != "" {
@@ -57,14 +103,7 @@ Examples:
 			fmt.Printf("Using distribution: %s\n", distName)
 		}
 
-		// Resolve partial version to full version
-		resolvedVer, err := lang.ResolveVersion(baseVer)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error resolving version: %v\n", err)
-			os.Exit(1)
-		}
-
-		if resolvedVer != baseVer {
+		if baseVer, _ := sources.ParseVersionAndDistribution(ver); resolvedVer != baseVer {
 			fmt.Printf("Resolved %s %s -> %s\n", langName, baseVer, resolvedVer)
 		}
 
@@ -75,9 +114,33 @@ Examples:
 			installVer = resolvedVer + "-" + dist
 		}
 
-		mgr := version.NewManager(cfg)
+		// Builds for another platform are only downloaded, never installed
+		if platform := sources.CurrentPlatform(); platform != sources.HostPlatform() {
+			dir, err := os.Getwd()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			saved, err := mgr.DownloadFor(langName, resolvedVer, dist, dir)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				printOfflineHint(err)
+				os.Exit(1)
+			}
+			fmt.Printf("Saved %s (built for %s, so not installed)\n", saved, platform)
+			return
+		}
+
 		if err := mgr.InstallWithDist(langName, resolvedVer, dist); err != nil {
+			var unsafeErr *version.UnsafeArchiveError
+			if errors.As(err, &unsafeErr) {
+				fmt.Fprintf(os.Stderr, "Error: refusing to install %s %s: the downloaded archive is unsafe\n", langName, installVer)
+				fmt.Fprintf(os.Stderr, "  %v\n", unsafeErr)
+				fmt.Fprintln(os.Stderr, "Nothing was installed. Limits can be raised with max_extract_size / max_extract_files in config.json.")
+				os.Exit(1)
+			}
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			printOfflineHint(err)
 			os.Exit(1)
 		}
 
@@ -86,7 +149,7 @@ Examples:
 		var response string
 		_, _ = fmt.Scanln(&response)
 		if response == "" || response == "y" || response == "Y" {
-			if err := mgr.Use(langName, installVer, false); err != nil {
+			if _, err := mgr.Use(langName, installVer, false); err != nil {
 				fmt.Fprintf(os.Stderr, "Error switching version: %v\n", err)
 				os.Exit(1)
 			}
@@ -95,6 +158,14 @@ Examples:
 	},
 }
 
+// printOfflineHint explains how to make an offline failure succeed next time
+func printOfflineHint(err error) {
+	if errors.Is(err, sources.ErrNotCached) {
+		fmt.Fprintln(os.Stderr, "Offline mode only uses release lists and downloads cached by earlier online runs.")
+		fmt.Fprintln(os.Stderr, "Run the same command once without --offline (and VERMAN_OFFLINE unset) to cache it.")
+	}
+}
+
 var uninstallCmd = &cobra.Command{
 	Use:   "uninstall <language> <version>",
 	Short: "Uninstall a specific version",
@@ -104,7 +175,7 @@ var uninstallCmd = &cobra.Command{
 		ver := args[1]
 
 		// Smart routing: "scala 3.x" -> "scala3"
-		if langName == "scala" && len(ver) > 0 && ver[0] == '3' {
+		if langName == "scala" && strings.HasPrefix(strings.TrimLeft(ver, "^~<>= "), "3") {
 			langName = "scala3"
 		}
 
@@ -118,6 +189,7 @@ var uninstallCmd = &cobra.Command{
 }
 
 func init() {
+	installCmd.Flags().String("platform", "", "Download for another platform as os/arch into the current directory, without installing (default: current system)")
 	rootCmd.AddCommand(installCmd)
 	rootCmd.AddCommand(uninstallCmd)
 }
@@ -3,10 +3,12 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/azdren/verman/internal/languages"
+	"github.com/azdren/verman/internal/semver"
 	"github.com/azdren/verman/internal/sources"
 	"github.com/azdren/verman/internal/version"
 	"github.com/spf13/cobra"
@@ -23,7 +25,8 @@ Examples:
   verman list              # List all installed versions
   verman list java         # List installed Java versions
   verman list java --all   # List all available Java versions (like SDKMAN)
-  verman list node -a      # List all available Node.js versions`,
+  verman list node -a      # List all available Node.js versions
+  verman list gradle -a --include-prereleases  # Include RCs and milestones`,
 	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		mgr := version.NewManager(cfg)
@@ -70,14 +73,24 @@ func listLanguage(mgr *version.Manager, langName string) {
 		return
 	}
 
+	aliases := mgr.AliasesByVersion(langName)
+
 	fmt.Printf("%s:\n", langName)
 	for _, v := range versions {
 		marker := "  "
 		if v == current {
 			marker = "* "
 		}
-		fmt.Printf("  %s%s\n", marker, v)
+		fmt.Printf("  %s%s%s\n", marker, v, aliasSuffix(aliases, v))
+	}
+}
+
+// aliasSuffix lists the user-defined aliases pointing at a version, e.g. "  (work, ci)"
+func aliasSuffix(aliases map[string][]string, v string) string {
+	if names := aliases[v]; len(names) > 0 {
+		return "  (" + strings.Join(names, ", ") + ")"
 	}
+	return ""
 }
 
 func listScalaVersions(mgr *version.Manager) {
@@ -87,6 +100,8 @@ func listScalaVersions(mgr *version.Manager) {
 
 	scala2Current, _ := mgr.GetCurrent("scala")
 	scala3Current, _ := mgr.GetCurrent("scala3")
+	scala2Aliases := mgr.AliasesByVersion("scala")
+	scala3Aliases := mgr.AliasesByVersion("scala3")
 
 	if len(scala2Versions) == 0 && len(scala3Versions) == 0 {
 		fmt.Println("No Scala versions installed")
@@ -99,14 +114,14 @@ func listScalaVersions(mgr *version.Manager) {
 		if v == scala3Current {
 			marker = "* "
 		}
-		fmt.Printf("  %s%s\n", marker, v)
+		fmt.Printf("  %s%s%s\n", marker, v, aliasSuffix(scala3Aliases, v))
 	}
 	for _, v := range scala2Versions {
 		marker := "  "
 		if v == scala2Current {
 			marker = "* "
 		}
-		fmt.Printf("  %s%s\n", marker, v)
+		fmt.Printf("  %s%s%s\n", marker, v, aliasSuffix(scala2Aliases, v))
 	}
 }
 
@@ -138,6 +153,7 @@ func listRemoteScalaVersions(mgr *version.Manager) {
 		fmt.Println("No versions found")
 		return
 	}
+	printCacheAge(scala2Src, scala3Src)
 
 	// Get installed versions (both scala and scala3)
 	installedMap := make(map[string]bool)
@@ -156,9 +172,7 @@ func listRemoteScalaVersions(mgr *version.Manager) {
 	scala3Current, _ := mgr.GetCurrent("scala3")
 
 	// Sort versions (newest first)
-	sort.Slice(allVersions, func(i, j int) bool {
-		return compareVersions(allVersions[i], allVersions[j]) > 0
-	})
+	semver.Sort(allVersions)
 
 	const width = 80
 
@@ -209,6 +223,7 @@ func listAllInstalled(mgr *version.Manager) {
 
 		hasAny = true
 		current, _ := mgr.GetCurrent(lang.Name())
+		aliases := mgr.AliasesByVersion(lang.Name())
 
 		fmt.Printf("%s:\n", lang.Name())
 		for _, v := range versions {
@@ -216,7 +231,7 @@ func listAllInstalled(mgr *version.Manager) {
 			if v == current {
 				marker = "* "
 			}
-			fmt.Printf("  %s%s\n", marker, v)
+			fmt.Printf("  %s%s%s\n", marker, v, aliasSuffix(aliases, v))
 		}
 		fmt.Println()
 	}
@@ -243,16 +258,28 @@ func listRemoteVersions(mgr *version.Manager, langName string) {
 
 	fmt.Printf("Fetching available %s versions...\n\n", src.DisplayName)
 
-	versions, err := src.FetchVersions()
+	releases, err := src.ListReleases()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error fetching versions: %v\n", err)
+		printOfflineHint(err)
 		os.Exit(1)
 	}
 
+	// LTS lines by version: a codename ("iron") or "true"
+	versions := make([]string, 0, len(releases))
+	lts := make(map[string]string)
+	for _, r := range releases {
+		versions = append(versions, r.Version)
+		if r.LTS != "" {
+			lts[r.Version] = strings.ToLower(r.LTS)
+		}
+	}
+
 	if len(versions) == 0 {
 		fmt.Printf("No versions found for %s\n", langName)
 		return
 	}
+	printCacheAge(src)
 
 	// Get installed versions for marking
 	installed, _ := mgr.ListInstalled(langName)
@@ -263,75 +290,123 @@ func listRemoteVersions(mgr *version.Manager, langName string) {
 	current, _ := mgr.GetCurrent(langName)
 
 	// Sort versions (newest first)
-	sort.Slice(versions, func(i, j int) bool {
-		return compareVersions(versions[i], versions[j]) > 0
-	})
+	semver.Sort(versions)
 
 	// Use SDKMAN-style table format for Java (with distributions)
 	if langName == "java" && len(src.Distributions) > 0 {
-		listJavaVersions(src, versions, installedMap, current)
+		listJavaVersions(src, versions, lts, installedMap, current)
 	} else {
-		listSimpleVersions(src, langName, versions, installedMap, current)
+		listSimpleVersions(src, langName, versions, lts, installedMap, current)
+	}
+}
+
+// printCacheAge notes when a release list came from the cache rather than
+// a fresh request, using the oldest of the given sources
+func printCacheAge(srcs ...*sources.Source) {
+	var oldest time.Duration
+	for _, src := range srcs {
+		if src == nil {
+			continue
+		}
+		if age, ok := src.CacheAge(); ok && age > oldest {
+			oldest = age
+		}
+	}
+	if oldest < time.Minute {
+		return
+	}
+	fmt.Printf("Release list cached %s ago (use --refresh to update)\n\n", sources.FormatAge(oldest))
+}
+
+// javaDistributions lists Java distributions in display order with their SDKMAN-style short ids
+var javaDistributions = []struct {
+	key     string
+	name    string
+	shortID string
+}{
+	{"temurin", "Temurin", "tem"},
+	{"corretto", "Corretto", "amzn"},
+	{"zulu", "Zulu", "zulu"},
+	{"liberica", "Liberica", "librca"},
+	{"microsoft", "Microsoft", "ms"},
+	{"sapmachine", "SapMachine", "sapmchn"},
+	{"graalce", "GraalVM CE", "graalce"},
+	{"semeru", "Semeru", "sem"},
+	{"oracle", "Oracle", "oracle"},
+}
+
+// fetchJavaReleaseVersions fetches concrete builds for every major version of a
+// distribution in parallel. Majors the vendor does not publish (or that fail to
+// load) fall back to the bare major version.
+func fetchJavaReleaseVersions(src *sources.Source, dist string, majors []string) []string {
+	results := make([][]string, len(majors))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, 4)
+
+	for i, major := range majors {
+		if !src.HasReleaseAPI(dist) {
+			results[i] = []string{major}
+			continue
+		}
+		wg.Add(1)
+		go func(i int, major string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			releases, err := src.FetchReleases(dist, major)
+			if err != nil || len(releases) == 0 {
+				results[i] = []string{major}
+				return
+			}
+			for _, r := range releases {
+				results[i] = append(results[i], r.Version)
+			}
+		}(i, major)
+	}
+	wg.Wait()
+
+	var versions []string
+	for _, r := range results {
+		versions = append(versions, r...)
 	}
+	return versions
 }
 
-// listJavaVersions displays Java versions in SDKMAN table format with distributions
-func listJavaVersions(src *sources.Source, versions []string, installedMap map[string]bool, current string) {
+// listJavaVersions displays Java versions in SDKMAN table format with distributions.
+// lts holds the long-term support major versions.
+func listJavaVersions(src *sources.Source, versions []string, lts map[string]string, installedMap map[string]bool, current string) {
 	const width = 80
 
 	// Header
 	fmt.Println(strings.Repeat("=", width))
-	fmt.Println("Available Java Versions for Windows x64")
+	fmt.Printf("Available Java Versions for %s\n", sources.CurrentPlatform())
 	fmt.Println(strings.Repeat("=", width))
-	fmt.Printf(" %-14s| %-4s| %-13s| %-8s| %-10s| %s\n",
-		"Vendor", "Use", "Version", "Dist", "Status", "Identifier")
+	fmt.Printf(" %-14s| %-4s| %-13s| %-4s| %-8s| %-10s| %s\n",
+		"Vendor", "Use", "Version", "LTS", "Dist", "Status", "Identifier")
 	fmt.Println(strings.Repeat("-", width))
 
-	// Group by distribution
-	distOrder := []struct {
-		key  string
-		name string
-	}{
-		{"temurin", "Temurin"},
-		{"corretto", "Corretto"},
-		{"zulu", "Zulu"},
-	}
-
-	for _, dist := range distOrder {
+	for _, dist := range javaDistributions {
 		d, ok := src.Distributions[dist.key]
 		if !ok {
 			continue
 		}
 
 		firstRow := true
-		for _, v := range versions {
-			// Build identifier with short distribution name
-			distShortId := dist.key
-			switch dist.key {
-			case "temurin":
-				distShortId = "tem"
-			case "corretto":
-				distShortId = "amzn"
-			}
-			identifier := v + "-" + distShortId // e.g., "21-tem", "21-amzn", "21-zulu"
+		for _, v := range fetchJavaReleaseVersions(src, dist.key, versions) {
+			identifier := v + "-" + dist.shortID // e.g., "21.0.2-tem", "21-amzn"
 
-			// Determine status
+			// Installed folders use whichever suffix the user typed (e.g., "amzn" or "corretto")
 			use := "   "
 			status := ""
-
-			// Check if this specific distribution is installed
-			fullIdentifier := v + "-" + sources.NormalizeDistribution(dist.key[:3])
-			if current == fullIdentifier {
-				use = ">>>"
-				status = "installed"
-			} else if installedMap[fullIdentifier] {
-				status = "installed"
-			} else if current == v || installedMap[v] {
-				// Check base version (without distribution)
-				if current == v {
+			for _, id := range []string{identifier, v + "-" + dist.key, v} {
+				if id == v && dist.key != src.DefaultDist {
+					continue // Unsuffixed installs belong to the default distribution
+				}
+				if current == id {
 					use = ">>>"
-					status = "installed"
-				} else if installedMap[v] {
+				}
+				if installedMap[id] {
 					status = "installed"
 				}
 			}
@@ -345,16 +420,13 @@ func listJavaVersions(src *sources.Source, versions []string, installedMap map[s
 				firstRow = false
 			}
 
-			distShort := dist.key
-			switch dist.key {
-			case "temurin":
-				distShort = "tem"
-			case "corretto":
-				distShort = "amzn"
+			ltsMark := ""
+			if _, ok := lts[strings.Split(v, ".")[0]]; ok {
+				ltsMark = "lts"
 			}
 
-			fmt.Printf(" %-14s| %-4s| %-13s| %-8s| %-10s| %s\n",
-				vendor, use, v, distShort, status, identifier)
+			fmt.Printf(" %-14s| %-4s| %-13s| %-4s| %-8s| %-10s| %s\n",
+				vendor, use, v, ltsMark, dist.shortID, status, identifier)
 		}
 		fmt.Println(strings.Repeat("-", width))
 	}
@@ -364,14 +436,16 @@ func listJavaVersions(src *sources.Source, versions []string, installedMap map[s
 	fmt.Println("Use the Identifier for installation:")
 	fmt.Println()
 	fmt.Println("    $ verman install java 21-tem")
+	fmt.Println("    $ verman install java 21.0.2-tem")
 	fmt.Println("    $ verman install java 17-amzn")
-	fmt.Println("    $ verman install java 21-zulu")
+	fmt.Println("    $ verman install java lts")
 	fmt.Println()
 	fmt.Println(strings.Repeat("=", width))
 }
 
-// listSimpleVersions displays versions in a simple column format (for non-Java tools)
-func listSimpleVersions(src *sources.Source, langName string, versions []string, installedMap map[string]bool, current string) {
+// listSimpleVersions displays versions in a simple column format (for non-Java tools).
+// LTS releases are labelled with their codename, e.g. "20.18.0 (lts/iron)".
+func listSimpleVersions(src *sources.Source, langName string, versions []string, lts map[string]string, installedMap map[string]bool, current string) {
 	const width = 80
 
 	// Header
@@ -379,9 +453,11 @@ func listSimpleVersions(src *sources.Source, langName string, versions []string,
 	fmt.Printf("Available %s Versions\n", src.DisplayName)
 	fmt.Println(strings.Repeat("=", width))
 
-	// Print in columns
-	const cols = 5
-	const colWidth = 15
+	// Print in columns, fewer and wider when there are LTS labels
+	cols, colWidth := 5, 15
+	if len(lts) > 0 {
+		cols, colWidth = 3, 26
+	}
 
 	for i, v := range versions {
 		marker := "    "
@@ -391,7 +467,15 @@ func listSimpleVersions(src *sources.Source, langName string, versions []string,
 			marker = "  * "
 		}
 
-		fmt.Printf("%s%-*s", marker, colWidth-4, v)
+		label := v
+		switch codename, ok := lts[v]; {
+		case ok && codename == "true":
+			label += " (lts)"
+		case ok:
+			label += " (lts/" + codename + ")"
+		}
+
+		fmt.Printf("%s%-*s", marker, colWidth-4, label)
 		if (i+1)%cols == 0 {
 			fmt.Println()
 		}
@@ -407,31 +491,11 @@ func listSimpleVersions(src *sources.Source, langName string, versions []string,
 	fmt.Println(strings.Repeat("=", width))
 	fmt.Println()
 	fmt.Printf("Use: verman install %s <version>\n", langName)
-}
-
-// compareVersions compares two version strings semantically
-func compareVersions(a, b string) int {
-	aParts := strings.Split(a, ".")
-	bParts := strings.Split(b, ".")
-
-	maxLen := len(aParts)
-	if len(bParts) > maxLen {
-		maxLen = len(bParts)
-	}
-
-	for i := 0; i < maxLen; i++ {
-		var aNum, bNum int
-		if i < len(aParts) {
-			_, _ = fmt.Sscanf(aParts[i], "%d", &aNum)
-		}
-		if i < len(bParts) {
-			_, _ = fmt.Sscanf(bParts[i], "%d", &bNum)
-		}
-		if aNum != bNum {
-			return aNum - bNum
-		}
+	if len(lts) > 0 {
+		fmt.Printf("     verman install %s lts          (or lts/<codename>, latest)\n", langName)
+	} else {
+		fmt.Printf("     verman install %s latest\n", langName)
 	}
-	return 0
 }
 
 func init() {
@@ -4,11 +4,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/azdren/verman/internal/config"
 	"github.com/azdren/verman/internal/languages"
+	"github.com/azdren/verman/internal/lock"
 	"github.com/azdren/verman/internal/sources"
+	"github.com/azdren/verman/internal/version"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var cfg *config.Config
@@ -30,6 +34,28 @@ Examples:
   verman list java          # List installed Java versions
   verman current            # Show all current versions
   verman detect             # Auto-detect versions from project files`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		applySettings(cmd.Flags())
+	},
+}
+
+// applySettings applies the global flags and VERMAN_OFFLINE to the packages
+// that use them
+func applySettings(flags *pflag.FlagSet) {
+	timeout, _ := flags.GetDuration("lock-timeout")
+	lock.SetTimeout(timeout)
+
+	offline, _ := flags.GetBool("offline")
+	if v := os.Getenv("VERMAN_OFFLINE"); v != "" && v != "0" && v != "false" {
+		offline = true
+	}
+	sources.SetOffline(offline)
+
+	refresh, _ := flags.GetBool("refresh")
+	sources.SetRefresh(refresh)
+
+	prereleases, _ := flags.GetBool("include-prereleases")
+	sources.SetIncludePrereleases(prereleases)
 }
 
 func Execute() {
@@ -55,6 +81,34 @@ func Execute() {
 		os.Exit(1)
 	}
 
+	// Cache release listings between runs (and for offline use)
+	sources.SetCacheDir(filepath.Join(cfg.CacheDir(), "metadata"))
+	if cfg.ReleaseCacheTTL != "" {
+		ttl, err := time.ParseDuration(cfg.ReleaseCacheTTL)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: invalid release_cache_ttl %q in config: %v\n", cfg.ReleaseCacheTTL, err)
+		} else {
+			sources.SetCacheTTL(ttl)
+		}
+	}
+
+	sources.SetGitHubToken(cfg.GitHubToken)
+	sources.SetGitHubHost(cfg.GitHubHost)
+	sources.SetMavenRepository(cfg.MavenRepository)
+
+	// Invoked through a shim ("java", "mvn"): run the tool, not the CLI.
+	// Its arguments are the tool's, so the flags keep their defaults, and
+	// it never goes to the network: "lts" or a range in a project file
+	// resolves from installed versions and cached release lists.
+	if name := shimName(); name != "" {
+		applySettings(rootCmd.PersistentFlags())
+		sources.SetOffline(true)
+		runShim(name, os.Args[1:])
+	}
+
+	// Clean up installs abandoned by a crashed or killed verman
+	_, _ = version.NewManager(cfg).SweepStaging()
+
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
 	}
@@ -63,4 +117,8 @@ func Execute() {
 func init() {
 	rootCmd.PersistentFlags().BoolP("global", "g", false, "Apply changes globally (persistent ENV vars)")
 	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Suppress output")
+	rootCmd.PersistentFlags().Bool("offline", false, "Use only cached release lists and downloads (or set VERMAN_OFFLINE=1)")
+	rootCmd.PersistentFlags().Bool("refresh", false, "Fetch release lists again instead of using the cache")
+	rootCmd.PersistentFlags().Bool("include-prereleases", false, "List and resolve release candidates, milestones and betas")
+	rootCmd.PersistentFlags().Duration("lock-timeout", lock.DefaultTimeout, "How long to wait for another verman process (0 to fail immediately)")
 }
@@ -6,8 +6,10 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 
+	"github.com/azdren/verman/internal/version"
 	"github.com/spf13/cobra"
 )
 
@@ -23,11 +25,18 @@ This command will:
 
 After running this command, restart your terminal and you can use 'verman' from anywhere.
 
+--uninstall takes out the environment variables "verman use --global" set:
+the "# >>> verman >>>" block in ~/.bashrc, ~/.zshrc and ~/.profile, fish
+universal variables, or the user environment on Windows (where ~/.verman/bin
+also comes off PATH). Installed versions are left in place.
+
 Examples:
   verman setup              # Full setup
-  verman setup --path-only  # Only add current location to PATH`,
+  verman setup --path-only  # Only add current location to PATH
+  verman setup --uninstall  # Remove verman's persistent environment variables`,
 	Run: func(cmd *cobra.Command, args []string) {
 		pathOnly, _ := cmd.Flags().GetBool("path-only")
+		uninstall, _ := cmd.Flags().GetBool("uninstall")
 
 		home, err := os.UserHomeDir()
 		if err != nil {
@@ -38,6 +47,11 @@ Examples:
 		vermanBinDir := filepath.Join(home, ".verman", "bin")
 		vermanExePath := filepath.Join(vermanBinDir, "verman.exe")
 
+		if uninstall {
+			uninstallSetup(vermanBinDir)
+			return
+		}
+
 		if pathOnly {
 			// Just add current directory to PATH
 			currentExe, err := os.Executable()
@@ -131,6 +145,55 @@ func addToPath(dir string) {
 	fmt.Printf("  Added: %s\n", dir)
 }
 
+// uninstallSetup removes the persistent environment variables verman set
+// and, on Windows, its bin directory from the user PATH
+func uninstallSetup(binDir string) {
+	removed, err := version.NewManager(cfg).RemoveGlobalEnv()
+	for _, location := range removed {
+		fmt.Printf("Removed verman environment from %s\n", location)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(removed) == 0 {
+		fmt.Println("No persistent verman environment variables found")
+	}
+
+	if runtime.GOOS == "windows" {
+		removeFromPath(binDir)
+	}
+	fmt.Println("\nRestart your terminal for changes to take effect.")
+}
+
+func removeFromPath(dir string) {
+	fmt.Print("Removing from PATH... ")
+
+	var kept []string
+	found := false
+	for _, p := range strings.Split(getCurrentUserPath(), ";") {
+		if p == "" {
+			continue
+		}
+		if strings.EqualFold(filepath.Clean(p), filepath.Clean(dir)) {
+			found = true
+			continue
+		}
+		kept = append(kept, p)
+	}
+	if !found {
+		fmt.Println("not in PATH")
+		return
+	}
+
+	if err := setUserPath(strings.Join(kept, ";")); err != nil {
+		fmt.Fprintf(os.Stderr, "\nError updating PATH: %v\n", err)
+		return
+	}
+	fmt.Println("done")
+	fmt.Printf("  Removed: %s\n", dir)
+}
+
 func getCurrentUserPath() string {
 	// Read from registry via PowerShell
 	// This gets the User PATH, not the combined PATH
@@ -187,5 +250,6 @@ func copyFile(src, dst string) error {
 
 func init() {
 	setupCmd.Flags().Bool("path-only", false, "Only add current location to PATH")
+	setupCmd.Flags().Bool("uninstall", false, "Remove verman's persistent environment variables (and PATH entry on Windows)")
 	rootCmd.AddCommand(setupCmd)
 }
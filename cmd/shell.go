@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/azdren/verman/internal/languages"
+	"github.com/azdren/verman/internal/version"
+	"github.com/spf13/cobra"
+)
+
+var shellCmd = &cobra.Command{
+	Use:   "shell <language> [version]",
+	Short: "Use a version in the current shell only",
+	Long: `Print shell code that switches the current shell session to an installed
+version. The session version wins over project version files and the global
+version (verman current, which, env and the shims all honour it) until the
+shell exits or --unset is used. Other terminals are unaffected.
+
+The output must be evaluated by the shell:
+  bash/zsh:    eval "$(verman shell java 17)"
+  fish:        verman shell java 17 | source
+  Nushell:     verman shell java 17 | from nuon | load-env
+  Elvish:      eval (verman shell java 17 | slurp)
+  PowerShell:  verman shell java 17 | Invoke-Expression
+  CMD:         verman shell java 17 --shell cmd > "%TEMP%\verman-shell.cmd" && call "%TEMP%\verman-shell.cmd"
+
+CMD output is batch-file code (a literal % is written as %%), so it has to
+be run from a file as above rather than pasted or run through for /f.
+
+With no version, prints the session version in effect.
+
+Examples:
+  verman shell java 17          # This shell uses Java 17
+  verman shell node lts         # Newest installed LTS release
+  verman shell java --unset     # Back to the project or global version`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		langName := args[0]
+		unset, _ := cmd.Flags().GetBool("unset")
+		shellName, _ := cmd.Flags().GetString("shell")
+
+		ver := ""
+		if len(args) == 2 {
+			ver = args[1]
+		}
+		// Smart routing: "scala 3.x" -> "scala3"
+		if langName == "scala" && strings.HasPrefix(strings.TrimLeft(ver, "^~<>= "), "3") {
+			langName = "scala3"
+		}
+
+		if _, ok := languages.Get(langName); !ok {
+			fmt.Fprintf(os.Stderr, "Unknown language: %s\n", langName)
+			fmt.Fprintf(os.Stderr, "Available: %v\n", languages.Names())
+			os.Exit(1)
+		}
+
+		if ver == "" && !unset {
+			session := os.Getenv(version.SessionVar(langName))
+			if session == "" {
+				fmt.Fprintf(os.Stderr, "No session version of %s set (see verman shell --help)\n", langName)
+				os.Exit(1)
+			}
+			fmt.Println(session)
+			return
+		}
+		if ver != "" && unset {
+			fmt.Fprintln(os.Stderr, "Error: --unset takes no version")
+			os.Exit(1)
+		}
+
+		sh := version.DetectShell()
+		if shellName != "" {
+			var err error
+			if sh, err = version.ParseShell(shellName); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		mgr := version.NewManager(cfg)
+		exports, err := mgr.SessionEnv(langName, ver, os.Environ())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(sh.Output(exports))
+	},
+}
+
+func init() {
+	shellCmd.Flags().Bool("unset", false, "Clear the session version")
+	shellCmd.Flags().String("shell", "", "Shell to generate code for (bash, zsh, fish, pwsh, cmd, nu, elvish); detected by default")
+	rootCmd.AddCommand(shellCmd)
+}
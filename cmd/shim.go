@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/azdren/verman/internal/version"
+	"github.com/spf13/cobra"
+)
+
+var reshimCmd = &cobra.Command{
+	Use:   "reshim",
+	Short: "Recreate the shims in ~/.verman/bin",
+	Long: `Recreate the shims for every installed version.
+
+Shims are links to verman named after each tool ("java", "mvn", "node"). Run
+from any directory, a shim picks the version in effect there - the session
+version ("verman shell"), else the nearest project version file, else the
+global version - and runs the real tool from it, so changing directory
+between projects switches versions without a shell hook.
+
+"verman use" creates shims for the version it switches to; run reshim after
+updating or moving verman, or to pick up tools of versions never used.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		mgr := version.NewManager(cfg)
+		if err := mgr.Reshim(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		dir, _ := version.ShimDir()
+		fmt.Printf("Shims updated in %s\n", dir)
+	},
+}
+
+// shimName returns the tool verman was invoked as through a shim, or ""
+// when it runs as itself
+func shimName() string {
+	name := filepath.Base(os.Args[0])
+	if ext := filepath.Ext(name); strings.EqualFold(ext, ".exe") {
+		name = strings.TrimSuffix(name, ext)
+	}
+	if strings.EqualFold(name, "verman") {
+		return ""
+	}
+	if _, ok := version.ShimLanguage(name); !ok {
+		return ""
+	}
+	return name
+}
+
+// runShim runs the real tool for the version in effect in the working
+// directory and exits with its exit code
+func runShim(name string, args []string) {
+	mgr := version.NewManager(cfg)
+	command, env, err := mgr.ShimCommand(name, workingDir(), os.Environ())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "verman: %v\n", err)
+		os.Exit(127)
+	}
+
+	code, err := version.RunCommand(append([]string{command}, args...), env)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "verman: %s: %v\n", name, err)
+	}
+	os.Exit(code)
+}
+
+func init() {
+	rootCmd.AddCommand(reshimCmd)
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/azdren/verman/internal/languages"
 	"github.com/azdren/verman/internal/version"
@@ -17,6 +18,9 @@ var useCmd = &cobra.Command{
 Examples:
   verman use java 21
   verman use node 20
+  verman use node "^20.10"    # Newest installed 20.x at or above 20.10
+  verman use node latest      # Newest installed version
+  verman use java lts         # Newest installed LTS release
   verman use -g scala 3.3.1   # Set globally (persistent)`,
 	Args: cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
@@ -26,7 +30,7 @@ Examples:
 		quiet, _ := cmd.Flags().GetBool("quiet")
 
 		// Smart routing: "scala 3.x" -> "scala3"
-		if langName == "scala" && len(ver) > 0 && ver[0] == '3' {
+		if langName == "scala" && strings.HasPrefix(strings.TrimLeft(ver, "^~<>= "), "3") {
 			langName = "scala3"
 		}
 
@@ -37,7 +41,8 @@ Examples:
 		}
 
 		mgr := version.NewManager(cfg)
-		if err := mgr.Use(langName, ver, global); err != nil {
+		ver, err := mgr.Use(langName, ver, global)
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
@@ -47,6 +52,9 @@ Examples:
 			if global {
 				fmt.Println("(Set globally - restart your terminal for changes to take effect)")
 			}
+			if session := os.Getenv(version.SessionVar(langName)); session != "" {
+				fmt.Printf("(This shell keeps %s %s from verman shell; run: verman shell %s --unset)\n", langName, session, langName)
+			}
 		}
 	},
 }
@@ -5,13 +5,15 @@ import (
 	"os"
 
 	"github.com/azdren/verman/internal/languages"
+	"github.com/azdren/verman/internal/version"
 	"github.com/spf13/cobra"
 )
 
 var whichCmd = &cobra.Command{
 	Use:   "which <language>",
 	Short: "Show path to current version",
-	Long: `Show the filesystem path to the currently active version.
+	Long: `Show the filesystem path to the currently active version: the session
+version, else the nearest project version file, else the global version.
 
 Examples:
   verman which java    # Show path to current Java
@@ -25,13 +27,17 @@ Examples:
 			os.Exit(1)
 		}
 
-		currentPath := cfg.GetCurrentPath(langName)
-		if _, err := os.Stat(currentPath); os.IsNotExist(err) {
+		sel, err := version.NewManager(cfg).Select(langName, workingDir())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if sel == nil {
 			fmt.Printf("No %s version set\n", langName)
 			os.Exit(1)
 		}
 
-		fmt.Println(currentPath)
+		fmt.Println(sel.Path)
 	},
 }
 
@@ -2,10 +2,11 @@ module github.com/azdren/verman
 
 go 1.24.0
 
-require github.com/spf13/cobra v1.8.0
-
 require (
-	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/spf13/pflag v1.0.5 // indirect
-	golang.org/x/sys v0.40.0 // indirect
+	github.com/spf13/cobra v1.8.0
+	github.com/spf13/pflag v1.0.5
+	github.com/ulikunitz/xz v0.5.17
+	golang.org/x/sys v0.40.0
 )
+
+require github.com/inconshreveable/mousetrap v1.1.0 // indirect
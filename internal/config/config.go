@@ -2,19 +2,48 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/azdren/verman/internal/lock"
 )
 
 type LanguageConfig struct {
 	CurrentVersion string `json:"current_version"`
 	InstallPath    string `json:"install_path"`
+
+	// User-defined version aliases, e.g. "work" -> "17-amzn"
+	Aliases map[string]string `json:"aliases,omitempty"`
 }
 
 type Config struct {
 	RootPath  string                    `json:"root_path"`
 	Languages map[string]LanguageConfig `json:"languages"`
-	path      string
+
+	// Archive extraction limits; zero uses the built-in defaults
+	MaxExtractSize  int64 `json:"max_extract_size,omitempty"`
+	MaxExtractFiles int   `json:"max_extract_files,omitempty"`
+
+	// Download cache size cap in bytes; zero uses the built-in default
+	CacheMaxSize int64 `json:"cache_max_size,omitempty"`
+
+	// How long release lists are reused before revalidating, e.g. "6h";
+	// empty uses the built-in default
+	ReleaseCacheTTL string `json:"release_cache_ttl,omitempty"`
+
+	// Token for GitHub API requests; the GITHUB_TOKEN environment variable wins
+	GitHubToken string `json:"github_token,omitempty"`
+
+	// GitHub Enterprise Server API host that may receive the token; only
+	// api.github.com does otherwise
+	GitHubHost string `json:"github_host,omitempty"`
+
+	// Maven repository (e.g., a Nexus mirror) for sources that do not name
+	// one; empty uses Maven Central
+	MavenRepository string `json:"maven_repository,omitempty"`
+
+	path string
 }
 
 var defaultLanguages = map[string]LanguageConfig{
@@ -73,6 +102,18 @@ func (c *Config) Save() error {
 		return nil
 	}
 
+	l, err := lock.Acquire(c.LockPath("config"))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = l.Release() }()
+
+	return c.write()
+}
+
+// write replaces config.json atomically, so readers never see a partial file.
+// Callers must hold the config lock.
+func (c *Config) write() error {
 	dir := filepath.Dir(c.path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
@@ -83,7 +124,92 @@ func (c *Config) Save() error {
 		return err
 	}
 
-	return os.WriteFile(c.path, data, 0644)
+	tmp, err := os.CreateTemp(dir, ".config-*.json")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, c.path)
+}
+
+// reload re-reads config.json so an update applies on top of changes other
+// processes made since this config was loaded. Callers must hold the config lock.
+func (c *Config) reload() error {
+	data, err := os.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	fresh := Config{path: c.path}
+	if err := json.Unmarshal(data, &fresh); err != nil {
+		return err
+	}
+	if fresh.Languages == nil {
+		fresh.Languages = make(map[string]LanguageConfig)
+	}
+	*c = fresh
+	return nil
+}
+
+// update applies fn under the config lock to the latest on-disk config and saves it
+func (c *Config) update(fn func()) error {
+	// Without a path (e.g., in tests) only the in-memory config changes
+	if c.path == "" {
+		fn()
+		return nil
+	}
+
+	l, err := lock.Acquire(c.LockPath("config"))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = l.Release() }()
+
+	if err := c.reload(); err != nil {
+		return err
+	}
+	fn()
+	return c.write()
+}
+
+// CacheDir returns the shared download cache directory. It lives next to
+// config.json so every versions root reuses it.
+func (c *Config) CacheDir() string {
+	if c.path == "" {
+		return filepath.Join(c.RootPath, ".cache")
+	}
+	return filepath.Join(filepath.Dir(c.path), "cache")
+}
+
+// LockPath returns the lock file guarding the named resource (a language
+// or "config"). Locks live next to config.json, or under the versions root
+// when the config has no file.
+func (c *Config) LockPath(name string) string {
+	dir := filepath.Join(c.RootPath, ".locks")
+	if c.path != "" {
+		dir = filepath.Join(filepath.Dir(c.path), "locks")
+	}
+	return filepath.Join(dir, name+".lock")
 }
 
 // SetPath sets the config file path (useful for testing)
@@ -100,10 +226,50 @@ func (c *Config) GetCurrentPath(lang string) string {
 }
 
 func (c *Config) SetCurrentVersion(lang, version string) error {
-	if langCfg, ok := c.Languages[lang]; ok {
-		langCfg.CurrentVersion = version
+	return c.update(func() {
+		if langCfg, ok := c.Languages[lang]; ok {
+			langCfg.CurrentVersion = version
+			c.Languages[lang] = langCfg
+		}
+	})
+}
+
+// Alias returns the version a user-defined alias points at
+func (c *Config) Alias(lang, name string) (string, bool) {
+	version, ok := c.Languages[lang].Aliases[name]
+	return version, ok
+}
+
+// SetAlias points an alias at a version, replacing any previous target
+func (c *Config) SetAlias(lang, name, version string) error {
+	return c.update(func() {
+		if c.Languages == nil {
+			c.Languages = make(map[string]LanguageConfig)
+		}
+		langCfg, ok := c.Languages[lang]
+		if !ok {
+			langCfg = LanguageConfig{InstallPath: lang}
+		}
+		if langCfg.Aliases == nil {
+			langCfg.Aliases = make(map[string]string)
+		}
+		langCfg.Aliases[name] = version
 		c.Languages[lang] = langCfg
-		return c.Save()
+	})
+}
+
+// RemoveAlias deletes an alias
+func (c *Config) RemoveAlias(lang, name string) error {
+	found := false
+	err := c.update(func() {
+		langCfg := c.Languages[lang]
+		if _, found = langCfg.Aliases[name]; found {
+			delete(langCfg.Aliases, name)
+			c.Languages[lang] = langCfg
+		}
+	})
+	if err == nil && !found {
+		err = fmt.Errorf("no %s alias named %s", lang, name)
 	}
-	return nil
+	return err
 }
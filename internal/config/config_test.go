@@ -114,3 +114,76 @@ func TestGetCurrentPath(t *testing.T) {
 		t.Errorf("Expected %s, got %s", expected, path)
 	}
 }
+
+func TestSetCurrentVersionKeepsConcurrentChanges(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".verman", "config.json")
+	languages := func() map[string]LanguageConfig {
+		return map[string]LanguageConfig{
+			"java": {CurrentVersion: "17", InstallPath: "java"},
+			"node": {CurrentVersion: "18", InstallPath: "node"},
+		}
+	}
+
+	// Two processes that loaded the same config
+	first := &Config{path: configPath, RootPath: tmpDir, Languages: languages()}
+	second := &Config{path: configPath, RootPath: tmpDir, Languages: languages()}
+	if err := first.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	if err := first.SetCurrentVersion("java", "21"); err != nil {
+		t.Fatalf("SetCurrentVersion failed: %v", err)
+	}
+	if err := second.SetCurrentVersion("node", "20"); err != nil {
+		t.Fatalf("SetCurrentVersion failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(configPath)
+	var reloaded Config
+	if err := json.Unmarshal(data, &reloaded); err != nil {
+		t.Fatalf("Config is not valid JSON: %v", err)
+	}
+	if reloaded.Languages["java"].CurrentVersion != "21" || reloaded.Languages["node"].CurrentVersion != "20" {
+		t.Errorf("Expected both updates to survive, got %+v", reloaded.Languages)
+	}
+
+	// No temp files are left next to config.json
+	entries, _ := os.ReadDir(filepath.Dir(configPath))
+	for _, e := range entries {
+		if e.Name() != "config.json" && e.Name() != "locks" {
+			t.Errorf("Unexpected leftover file: %s", e.Name())
+		}
+	}
+}
+
+func TestAliases(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".verman", "config.json")
+	cfg := &Config{path: configPath, RootPath: tmpDir, Languages: map[string]LanguageConfig{}}
+
+	if err := cfg.SetAlias("java", "work", "17-amzn"); err != nil {
+		t.Fatalf("SetAlias failed: %v", err)
+	}
+	if v, ok := cfg.Alias("java", "work"); !ok || v != "17-amzn" {
+		t.Errorf("Expected work -> 17-amzn, got %q (%v)", v, ok)
+	}
+
+	// Persisted alongside the language's other settings
+	data, _ := os.ReadFile(configPath)
+	var reloaded Config
+	_ = json.Unmarshal(data, &reloaded)
+	if reloaded.Languages["java"].Aliases["work"] != "17-amzn" || reloaded.Languages["java"].InstallPath != "java" {
+		t.Errorf("Alias not persisted: %+v", reloaded.Languages["java"])
+	}
+
+	if err := cfg.RemoveAlias("java", "work"); err != nil {
+		t.Fatalf("RemoveAlias failed: %v", err)
+	}
+	if _, ok := cfg.Alias("java", "work"); ok {
+		t.Error("Alias still present after RemoveAlias")
+	}
+	if err := cfg.RemoveAlias("java", "work"); err == nil {
+		t.Error("Expected error removing a missing alias")
+	}
+}
@@ -43,6 +43,10 @@ type Language interface {
 	// e.g., "20" -> "20.18.0" for Node.js
 	ResolveVersion(version string) (string, error)
 
+	// ResolveVersionWithDist resolves a partial version for a specific distribution,
+	// using vendor release metadata where available (e.g., "21.0" -> "21.0.2")
+	ResolveVersionWithDist(version, distribution string) (string, error)
+
 	// GetDownloadURL returns the download URL for a specific version
 	GetDownloadURL(version string) (string, error)
 
@@ -58,7 +62,8 @@ type Language interface {
 	// GetExtractPattern returns the expected folder name inside the archive
 	GetExtractPattern(version string) string
 
-	// GetDownloadType returns "zip" (default) or "file" for single file downloads
+	// GetDownloadType returns "zip" (default), "tar.gz", "tar.xz", "auto" to
+	// detect the archive format after download, or "file" for single file downloads
 	GetDownloadType() string
 
 	// PostInstall runs any post-installation steps
@@ -72,6 +77,11 @@ type Language interface {
 
 	// GetChecksumURL returns the URL to fetch SHA256 checksum (empty if not available)
 	GetChecksumURL(version, distribution string) string
+
+	// GetChecksum returns the checksum from the version's release metadata or
+	// its Maven sidecar file: SHA256 hex, or "sha1:<hex>" (empty if not
+	// available). Either may be fetched over the network.
+	GetChecksum(version, distribution string) string
 }
 
 // SourceLanguage adapts a Source to the Language interface
@@ -107,11 +117,23 @@ func (sl *SourceLanguage) ResolveVersion(version string) (string, error) {
 	return sl.source.ResolveVersion(version)
 }
 
+func (sl *SourceLanguage) ResolveVersionWithDist(version, distribution string) (string, error) {
+	return sl.source.ResolveVersionWithDist(version, distribution)
+}
+
 func (sl *SourceLanguage) GetDownloadURL(version string) (string, error) {
-	return sl.source.GetDownloadURL(version), nil
+	return sl.GetDownloadURLWithDist(version, "")
 }
 
 func (sl *SourceLanguage) GetDownloadURLWithDist(version, distribution string) (string, error) {
+	// Exact builds come straight from vendor release metadata
+	release, err := sl.source.FindRelease(version, distribution)
+	if err != nil {
+		return "", err
+	}
+	if release != nil && release.URL != "" {
+		return release.URL, nil
+	}
 	return sl.source.GetDownloadURLWithDist(version, distribution), nil
 }
 
@@ -195,6 +217,8 @@ func (sl *SourceLanguage) VersionCommand() string {
 		return "gradle --version"
 	case "maven":
 		return "mvn --version"
+	case "ant":
+		return "ant -version"
 	case "sbt":
 		return "sbt --version"
 	case "mill":
@@ -212,6 +236,15 @@ func (sl *SourceLanguage) GetChecksumURL(version, distribution string) string {
 	return sl.source.GetChecksumURL(version, distribution)
 }
 
+// GetChecksum looks the version up in the source's release list (cached,
+// see FindRelease) and otherwise downloads the Maven sidecar checksum
+func (sl *SourceLanguage) GetChecksum(version, distribution string) string {
+	if release, err := sl.source.FindRelease(version, distribution); err == nil && release != nil && release.Checksum != "" {
+		return release.Checksum
+	}
+	return sl.source.SidecarChecksum(version, distribution)
+}
+
 // Registry holds all supported languages
 var Registry = make(map[string]Language)
 
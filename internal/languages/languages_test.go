@@ -60,8 +60,8 @@ func TestNodeValidateVersion(t *testing.T) {
 		t.Fatal("Node language not found")
 	}
 
-	valid := []string{"18", "20", "18.19.0", "v20.10.0", "20.10.0"}
-	invalid := []string{"", "lts", "latest", "node18"}
+	valid := []string{"18", "20", "18.19.0", "v20.10.0", "20.10.0", "lts", "lts/iron"}
+	invalid := []string{"", "latest", "node18"}
 
 	for _, v := range valid {
 		if !node.ValidateVersion(v) {
@@ -0,0 +1,133 @@
+// Package lock provides cross-process file locks guarding verman's shared
+// state (installed versions, "current" links and config.json)
+package lock
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout is how long Acquire waits for another verman process
+const DefaultTimeout = 5 * time.Minute
+
+// pollInterval is how often a contended lock is retried
+const pollInterval = 100 * time.Millisecond
+
+var (
+	timeoutMu sync.Mutex
+	timeout   = DefaultTimeout
+)
+
+// SetTimeout sets how long Acquire waits for a lock. Zero fails immediately
+// when the lock is held.
+func SetTimeout(d time.Duration) {
+	timeoutMu.Lock()
+	defer timeoutMu.Unlock()
+	timeout = d
+}
+
+// Timeout returns the current lock wait timeout
+func Timeout() time.Duration {
+	timeoutMu.Lock()
+	defer timeoutMu.Unlock()
+	return timeout
+}
+
+// Lock is an exclusive lock on a file, held until Release.
+// The operating system drops it if the process dies.
+type Lock struct {
+	file *os.File
+}
+
+// TimeoutError is returned when a lock stays held by another process
+type TimeoutError struct {
+	Path    string
+	PID     int // Holder's PID, 0 if unknown
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	if e.PID == 0 {
+		return fmt.Sprintf("timed out after %s waiting for lock %s", e.Timeout, e.Path)
+	}
+	return fmt.Sprintf("timed out after %s waiting for lock %s held by PID %d", e.Timeout, e.Path, e.PID)
+}
+
+// Acquire takes the lock at path, waiting up to Timeout() while another
+// process holds it. The holder's PID is stored in the file for diagnostics.
+func Acquire(path string) (*Lock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	wait := Timeout()
+	deadline := time.Now().Add(wait)
+	waiting := false
+	for {
+		ok, err := tryLock(f)
+		if err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if ok {
+			break
+		}
+
+		pid := holderPID(f)
+		if !time.Now().Before(deadline) {
+			_ = f.Close()
+			return nil, &TimeoutError{Path: path, PID: pid, Timeout: wait}
+		}
+		if !waiting {
+			waiting = true
+			if pid != 0 {
+				fmt.Fprintf(os.Stderr, "Waiting for lock held by PID %d (%s)...\n", pid, path)
+			} else {
+				fmt.Fprintf(os.Stderr, "Waiting for lock %s...\n", path)
+			}
+		}
+		time.Sleep(pollInterval)
+	}
+
+	// Record ourselves as the holder
+	if err := f.Truncate(0); err == nil {
+		_, _ = f.WriteAt([]byte(strconv.Itoa(os.Getpid())), 0)
+	}
+	return &Lock{file: f}, nil
+}
+
+// Release unlocks and closes the lock file. The file itself is left in
+// place, since removing it would race with processes waiting on it.
+func (l *Lock) Release() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	_ = l.file.Truncate(0)
+	err := unlock(l.file)
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+	l.file = nil
+	return err
+}
+
+// holderPID reads the PID recorded by the current holder
+func holderPID(f *os.File) int {
+	data, err := io.ReadAll(io.NewSectionReader(f, 0, 32))
+	if err != nil {
+		return 0
+	}
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	return pid
+}
@@ -0,0 +1,98 @@
+package lock
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestAcquireRelease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "locks", "java.lock")
+
+	l, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != strconv.Itoa(os.Getpid()) {
+		t.Errorf("Expected lock file to hold our PID, got %q", data)
+	}
+
+	if err := l.Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if err := l.Release(); err != nil {
+		t.Errorf("Second Release should be a no-op: %v", err)
+	}
+
+	// Free again after release
+	l, err = Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire after release failed: %v", err)
+	}
+	_ = l.Release()
+}
+
+func TestAcquireTimeout(t *testing.T) {
+	defer SetTimeout(Timeout())
+	SetTimeout(200 * time.Millisecond)
+
+	path := filepath.Join(t.TempDir(), "node.lock")
+	held, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	defer func() { _ = held.Release() }()
+
+	start := time.Now()
+	_, err = Acquire(path)
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("Expected TimeoutError, got %v", err)
+	}
+	if timeoutErr.PID != os.Getpid() {
+		t.Errorf("Expected holder PID %d, got %d", os.Getpid(), timeoutErr.PID)
+	}
+	if time.Since(start) < 200*time.Millisecond {
+		t.Error("Expected Acquire to wait for the timeout")
+	}
+}
+
+func TestAcquireWaitsForRelease(t *testing.T) {
+	defer SetTimeout(Timeout())
+	SetTimeout(5 * time.Second)
+
+	path := filepath.Join(t.TempDir(), "go.lock")
+	held, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	go func() {
+		time.Sleep(150 * time.Millisecond)
+		_ = held.Release()
+	}()
+
+	l, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Expected lock after holder released it: %v", err)
+	}
+	_ = l.Release()
+}
+
+func TestAcquireNoWait(t *testing.T) {
+	defer SetTimeout(Timeout())
+	SetTimeout(0)
+
+	path := filepath.Join(t.TempDir(), "config.lock")
+	held, _ := Acquire(path)
+	defer func() { _ = held.Release() }()
+
+	if _, err := Acquire(path); err == nil {
+		t.Error("Expected immediate failure with zero timeout")
+	}
+}
@@ -0,0 +1,339 @@
+package semver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Constraint is a version range in npm/cargo syntax:
+//
+//	^20.10       >=20.10.0 <21.0.0 (^0.2.3 is >=0.2.3 <0.3.0)
+//	~3.3         >=3.3.0 <3.4.0 (~3 is >=3.0.0 <4.0.0)
+//	>=17 <22     comparators separated by spaces or commas must all hold
+//	1.2 - 1.4    inclusive hyphen range (<1.5.0)
+//	2.13.x, 3.*  wildcards; a bare partial version is a wildcard too
+//	^18 || ^20   either range
+type Constraint struct {
+	raw      string
+	sets     [][]interval // Alternatives, each a list of intervals that must all hold
+	versions []string     // Versions named in the expression
+}
+
+// interval is a range of versions; a nil bound is unbounded
+type interval struct {
+	lo, hi       *Version
+	loInc, hiInc bool
+}
+
+// IsConstraint reports whether s is a range expression rather than a
+// version (partial versions and ".x" wildcards are not)
+func IsConstraint(s string) bool {
+	s = strings.TrimSpace(s)
+	if s == "*" || strings.Contains(s, "||") || strings.ContainsAny(s, " ,") {
+		return true
+	}
+	return s != "" && strings.ContainsRune("^~<>=", rune(s[0]))
+}
+
+// ParseConstraint parses a range expression
+func ParseConstraint(s string) (*Constraint, error) {
+	c := &Constraint{raw: strings.TrimSpace(s)}
+	for _, alt := range strings.Split(c.raw, "||") {
+		set, err := c.parseSet(strings.TrimSpace(alt))
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint %q: %w", s, err)
+		}
+		c.sets = append(c.sets, set)
+	}
+	return c, nil
+}
+
+func (c *Constraint) parseSet(s string) ([]interval, error) {
+	// Hyphen range: "1.2 - 1.4"
+	if lo, hi, ok := strings.Cut(s, " - "); ok {
+		from, err := c.parsePartial(strings.TrimSpace(lo))
+		if err != nil {
+			return nil, err
+		}
+		to, err := c.parsePartial(strings.TrimSpace(hi))
+		if err != nil {
+			return nil, err
+		}
+		iv := interval{lo: from.floor(), loInc: true}
+		if to.complete() {
+			iv.hi, iv.hiInc = to.floor(), true
+		} else {
+			iv.hi = to.next()
+		}
+		return []interval{iv}, nil
+	}
+
+	// Allow "> = 17" style spacing by gluing operators to their operand
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' })
+	var terms []string
+	for i := 0; i < len(fields); i++ {
+		term := fields[i]
+		if strings.Trim(term, "^~<>=") == "" && i+1 < len(fields) {
+			term += fields[i+1]
+			i++
+		}
+		terms = append(terms, term)
+	}
+	if len(terms) == 0 {
+		return []interval{{}}, nil
+	}
+
+	var set []interval
+	for _, term := range terms {
+		iv, err := c.parseTerm(term)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, iv)
+	}
+	return set, nil
+}
+
+func (c *Constraint) parseTerm(term string) (interval, error) {
+	op := term[:len(term)-len(strings.TrimLeft(term, "^~<>="))]
+	p, err := c.parsePartial(term[len(op):])
+	if err != nil {
+		return interval{}, err
+	}
+	if p.any() {
+		switch op {
+		case "", "=", ">=", "<=", "^", "~":
+			return interval{}, nil
+		}
+		return interval{}, fmt.Errorf("%q matches nothing", term)
+	}
+
+	switch op {
+	case "", "=":
+		if p.complete() {
+			return interval{lo: p.floor(), loInc: true, hi: p.floor(), hiInc: true}, nil
+		}
+		return interval{lo: p.floor(), loInc: true, hi: p.next()}, nil
+	case ">=":
+		return interval{lo: p.floor(), loInc: true}, nil
+	case ">":
+		if p.complete() {
+			return interval{lo: p.floor()}, nil
+		}
+		return interval{lo: p.next(), loInc: true}, nil
+	case "<":
+		return interval{hi: p.lowest()}, nil
+	case "<=":
+		if p.complete() {
+			return interval{hi: p.floor(), hiInc: true}, nil
+		}
+		return interval{hi: p.next()}, nil
+	case "~":
+		keep := 1
+		if len(p.parts) >= 2 {
+			keep = 2
+		}
+		return interval{lo: p.floor(), loInc: true, hi: p.bump(keep)}, nil
+	case "^":
+		// Bump the first non-zero component, or the last one given
+		keep := len(p.parts)
+		for i, n := range p.parts {
+			if n != 0 {
+				keep = i + 1
+				break
+			}
+		}
+		return interval{lo: p.floor(), loInc: true, hi: p.bump(keep)}, nil
+	}
+	return interval{}, fmt.Errorf("unknown operator %q", op)
+}
+
+// partial is a version with possibly missing or wildcard components
+type partial struct {
+	parts []int // Given numeric components
+	full  Version
+}
+
+func (c *Constraint) parsePartial(s string) (partial, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(s), "v"), "V")
+	if s == "" || s == "*" || s == "x" || s == "X" {
+		return partial{}, nil
+	}
+
+	// Drop wildcard components: "2.13.x" -> "2.13"
+	comps := strings.Split(s, ".")
+	for i, comp := range comps {
+		if comp == "x" || comp == "X" || comp == "*" {
+			comps = comps[:i]
+			break
+		}
+	}
+	s = strings.Join(comps, ".")
+
+	v := Parse(s)
+	if len(v.Release) == 0 {
+		return partial{}, fmt.Errorf("%q is not a version", s)
+	}
+	c.versions = append(c.versions, s)
+	return partial{parts: v.Release, full: v}, nil
+}
+
+func (p partial) any() bool { return len(p.parts) == 0 }
+
+// complete reports whether major, minor and patch were all given (or a
+// prerelease/qualifier pins the version)
+func (p partial) complete() bool {
+	return len(p.parts) >= 3 || len(p.full.Pre) > 0 || len(p.full.Qualifier) > 0
+}
+
+// floor returns the lowest version the partial names
+func (p partial) floor() *Version {
+	v := p.full
+	return &v
+}
+
+// lowest returns the lowest version the partial names including its
+// prereleases, so "<22" excludes "22-ea" as well as "22"
+func (p partial) lowest() *Version {
+	if p.full.IsPrerelease() || len(p.full.Qualifier) > 0 {
+		return p.floor()
+	}
+	return &Version{Release: p.parts, stage: stageSnapshot, Pre: []string{}}
+}
+
+// next returns the first version past the partial: "1.2" -> "1.3"
+func (p partial) next() *Version {
+	return p.bump(len(p.parts))
+}
+
+// bump returns the version with the first keep components kept and the
+// last of them incremented: bump("1.2.3", 2) -> "1.3". The result is the
+// lowest prerelease of that version, so "<1.3" also excludes "1.3.0-rc1".
+func (p partial) bump(keep int) *Version {
+	parts := append([]int(nil), p.parts[:keep]...)
+	parts[keep-1]++
+	return &Version{Release: parts, stage: stageSnapshot, Pre: []string{}}
+}
+
+// Check reports whether a version satisfies the constraint. Build
+// metadata ("+13") is ignored.
+func (c *Constraint) Check(version string) bool {
+	v := Parse(version)
+	v.Build = nil
+	for _, set := range c.sets {
+		ok := true
+		for _, iv := range set {
+			if !iv.contains(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (iv interval) contains(v Version) bool {
+	if iv.lo != nil {
+		if c := v.Compare(*iv.lo); c < 0 || (c == 0 && !iv.loInc) {
+			return false
+		}
+	}
+	if iv.hi != nil {
+		if c := v.Compare(*iv.hi); c > 0 || (c == 0 && !iv.hiInc) {
+			return false
+		}
+	}
+	return true
+}
+
+// intersect narrows iv to the part that also lies in o
+func (iv interval) intersect(o interval) interval {
+	if o.lo != nil {
+		if iv.lo == nil {
+			iv.lo, iv.loInc = o.lo, o.loInc
+		} else if c := o.lo.Compare(*iv.lo); c > 0 || (c == 0 && !o.loInc) {
+			iv.lo, iv.loInc = o.lo, o.loInc
+		}
+	}
+	if o.hi != nil {
+		if iv.hi == nil {
+			iv.hi, iv.hiInc = o.hi, o.hiInc
+		} else if c := o.hi.Compare(*iv.hi); c < 0 || (c == 0 && !o.hiInc) {
+			iv.hi, iv.hiInc = o.hi, o.hiInc
+		}
+	}
+	return iv
+}
+
+func (iv interval) empty() bool {
+	if iv.lo == nil || iv.hi == nil {
+		return false
+	}
+	c := iv.lo.Compare(*iv.hi)
+	return c > 0 || (c == 0 && !(iv.loInc && iv.hiInc))
+}
+
+// AllowsMajor reports whether any version with the given major version
+// could satisfy the constraint. Vendor APIs list releases per major, so
+// this picks which majors to fetch.
+func (c *Constraint) AllowsMajor(major int) bool {
+	for _, set := range c.sets {
+		iv := interval{
+			lo:    &Version{Release: []int{major}, stage: stageSnapshot, Pre: []string{}},
+			loInc: true,
+			hi:    &Version{Release: []int{major + 1}, stage: stageSnapshot, Pre: []string{}},
+		}
+		for _, o := range set {
+			iv = iv.intersect(o)
+		}
+		if !iv.empty() {
+			return true
+		}
+	}
+	return false
+}
+
+// Versions returns the versions named in the expression, e.g. ["17", "22"]
+// for ">=17 <22"
+func (c *Constraint) Versions() []string {
+	return c.versions
+}
+
+func (c *Constraint) String() string {
+	return c.raw
+}
+
+// Nearest returns up to n of the given versions closest to the constraint's
+// first named version, for "did you mean" style errors. The result is
+// ordered newest first.
+func Nearest(versions []string, c *Constraint, n int) []string {
+	if len(versions) == 0 || n <= 0 {
+		return nil
+	}
+	sorted := append([]string(nil), versions...)
+	Sort(sorted)
+	if len(c.versions) == 0 || len(sorted) <= n {
+		if len(sorted) > n {
+			sorted = sorted[:n]
+		}
+		return sorted
+	}
+
+	// sorted is newest first; find where the anchor would go
+	anchor := Parse(c.versions[0])
+	idx := sort.Search(len(sorted), func(i int) bool {
+		return Parse(sorted[i]).Compare(anchor) <= 0
+	})
+	start := idx - n/2
+	if start < 0 {
+		start = 0
+	}
+	if start+n > len(sorted) {
+		start = len(sorted) - n
+	}
+	return sorted[start : start+n]
+}
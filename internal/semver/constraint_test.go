@@ -0,0 +1,101 @@
+package semver
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConstraintCheck(t *testing.T) {
+	tests := []struct {
+		expr    string
+		version string
+		want    bool
+	}{
+		{"^20.10", "20.10.0", true},
+		{"^20.10", "20.18.1", true},
+		{"^20.10", "20.9.0", false},
+		{"^20.10", "21.0.0", false},
+		{"^0.2.3", "0.2.9", true},
+		{"^0.2.3", "0.3.0", false},
+		{"~3.3", "3.3.4", true},
+		{"~3.3", "3.4.0", false},
+		{"~3", "3.9.0", true},
+		{"~1.2.3", "1.2.9", true},
+		{"~1.2.3", "1.3.0", false},
+		{">=17 <22", "17", true},
+		{">=17 <22", "21.0.2+13", true},
+		{">=17 <22", "22", false},
+		{">=17 <22", "16.0.2", false},
+		{">= 17, < 22", "21", true},
+		{">1.2", "1.2.9", false},
+		{">1.2", "1.3.0", true},
+		{"<=1.2", "1.2.9", true},
+		{"<=1.2", "1.3.0", false},
+		{"<1.3", "1.3.0-rc1", false},
+		{"1.2 - 1.4", "1.4.7", true},
+		{"1.2 - 1.4", "1.5.0", false},
+		{"1.2.0 - 1.4.0", "1.4.1", false},
+		{"=2.13", "2.13.12", true},
+		{"2.13.x", "2.13.12", true},
+		{"2.13.x", "2.12.18", false},
+		{"*", "1.0.0", true},
+		{"^18 || ^20", "18.19.0", true},
+		{"^18 || ^20", "19.0.0", false},
+		{"^18 || ^20", "20.1.0", true},
+		{"=21.0.2", "21.0.2+13", true},
+	}
+
+	for _, tt := range tests {
+		c, err := ParseConstraint(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseConstraint(%q): %v", tt.expr, err)
+		}
+		if got := c.Check(tt.version); got != tt.want {
+			t.Errorf("%q.Check(%q) = %v, want %v", tt.expr, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestIsConstraint(t *testing.T) {
+	for _, s := range []string{"^20", "~3.3", ">=17 <22", "*", "^18 || ^20", "1.2 - 1.4", "=2.13"} {
+		if !IsConstraint(s) {
+			t.Errorf("IsConstraint(%q) = false", s)
+		}
+	}
+	for _, s := range []string{"20", "2.13", "2.13.x", "21-amzn", "3.3.0-RC1", ""} {
+		if IsConstraint(s) {
+			t.Errorf("IsConstraint(%q) = true", s)
+		}
+	}
+}
+
+func TestParseConstraintErrors(t *testing.T) {
+	for _, s := range []string{"^abc", ">*", ">=17 <"} {
+		if _, err := ParseConstraint(s); err == nil {
+			t.Errorf("ParseConstraint(%q): expected error", s)
+		}
+	}
+}
+
+func TestAllowsMajor(t *testing.T) {
+	c, _ := ParseConstraint(">=17.0.5 <22 || ^8")
+	for major, want := range map[int]bool{8: true, 11: false, 17: true, 21: true, 22: false} {
+		if got := c.AllowsMajor(major); got != want {
+			t.Errorf("AllowsMajor(%d) = %v, want %v", major, got, want)
+		}
+	}
+
+	c, _ = ParseConstraint("<=17")
+	if !c.AllowsMajor(17) || c.AllowsMajor(18) {
+		t.Error("<=17 should allow 17 and not 18")
+	}
+}
+
+func TestNearest(t *testing.T) {
+	versions := []string{"16.20.2", "18.19.0", "20.11.0", "21.6.1", "22.0.0"}
+	c, _ := ParseConstraint("^19.2")
+	got := strings.Join(Nearest(versions, c, 2), ",")
+	if got != "20.11.0,18.19.0" {
+		t.Errorf("Nearest: got %s", got)
+	}
+}
@@ -0,0 +1,250 @@
+// Package semver parses and orders tool versions: dotted release numbers
+// followed by optional prerelease qualifiers and build suffixes, in the
+// styles used by Java, Node.js, Go, Gradle, Maven, Kotlin and Scala
+// (e.g., "21.0.2+13", "1.23rc1", "8.5-rc-1", "1.9.20-Beta", "3.3.0-RC1").
+package semver
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Prerelease stages, lowest first. Releases (and unrecognized qualifiers
+// such as "-LTS") rank above every prerelease.
+const (
+	stageSnapshot = iota // snapshot, nightly, dev, ea, timestamped builds
+	stageAlpha
+	stageBeta
+	stageMilestone // milestone, M, preview
+	stageRC
+	stageRelease
+)
+
+// stages maps prerelease qualifiers to their stage
+var stages = map[string]int{
+	"snapshot":  stageSnapshot,
+	"nightly":   stageSnapshot,
+	"dev":       stageSnapshot,
+	"ea":        stageSnapshot,
+	"alpha":     stageAlpha,
+	"a":         stageAlpha,
+	"beta":      stageBeta,
+	"b":         stageBeta,
+	"milestone": stageMilestone,
+	"m":         stageMilestone,
+	"preview":   stageMilestone,
+	"pre":       stageMilestone,
+	"rc":        stageRC,
+	"cr":        stageRC,
+}
+
+// releaseQualifiers mark a final release (Maven's "3.0-final", "1.0.GA")
+var releaseQualifiers = map[string]bool{"final": true, "ga": true, "release": true}
+
+// Version is a parsed version string
+type Version struct {
+	Original  string
+	Release   []int    // Dotted numeric components, e.g. [8, 5]
+	Pre       []string // Prerelease identifiers, lower-cased, e.g. ["rc", "1"]
+	Qualifier []string // Other suffix identifiers, e.g. ["lts"]
+	Build     []string // Identifiers after "+", e.g. ["13"]
+
+	stage int
+}
+
+// Parse parses a version. It never fails: anything after the numeric
+// release is classified as prerelease, qualifier or build.
+func Parse(s string) Version {
+	v := Version{Original: s, stage: stageRelease}
+	s = strings.TrimSpace(s)
+	if len(s) > 1 && (s[0] == 'v' || s[0] == 'V') && isDigit(s[1]) {
+		s = s[1:]
+	}
+
+	if idx := strings.IndexByte(s, '+'); idx >= 0 {
+		v.Build = identifiers(s[idx+1:])
+		s = s[:idx]
+	}
+
+	// Numeric release: digits separated by single dots
+	i := 0
+	for i < len(s) {
+		start := i
+		for i < len(s) && isDigit(s[i]) {
+			i++
+		}
+		if i == start {
+			break
+		}
+		n, _ := strconv.Atoi(s[start:i])
+		v.Release = append(v.Release, n)
+		if i+1 < len(s) && s[i] == '.' && isDigit(s[i+1]) {
+			i++
+			continue
+		}
+		break
+	}
+
+	rest := identifiers(s[i:])
+	if len(rest) == 0 {
+		return v
+	}
+
+	first := rest[0]
+	switch stage, ok := stages[first]; {
+	case ok:
+		v.stage = stage
+		v.Pre = rest
+	case releaseQualifiers[first]:
+		v.Qualifier = rest[1:]
+	case len(first) >= 8 && isNumber(first):
+		// Timestamped snapshot, e.g. Gradle's "8.6-20231101220000"
+		v.stage = stageSnapshot
+		v.Pre = rest
+	default:
+		v.Qualifier = rest
+	}
+	return v
+}
+
+// identifiers splits a suffix into lower-case identifiers at separators
+// and letter/digit boundaries: "-RC1" -> ["rc", "1"], "rc-1" -> ["rc", "1"]
+func identifiers(s string) []string {
+	var ids []string
+	start := -1
+	for i := 0; i <= len(s); i++ {
+		boundary := i == len(s) || s[i] == '-' || s[i] == '.' || s[i] == '_'
+		if !boundary && start >= 0 && isDigit(s[i]) != isDigit(s[i-1]) {
+			ids = append(ids, strings.ToLower(s[start:i]))
+			start = i
+			continue
+		}
+		if boundary {
+			if start >= 0 {
+				ids = append(ids, strings.ToLower(s[start:i]))
+			}
+			start = -1
+		} else if start < 0 {
+			start = i
+		}
+	}
+	return ids
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isNumber(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// IsPrerelease reports whether the version is an alpha, beta, milestone,
+// release candidate or snapshot build
+func (v Version) IsPrerelease() bool {
+	return v.stage < stageRelease
+}
+
+// IsPrerelease reports whether a version string is a prerelease
+func IsPrerelease(s string) bool {
+	return Parse(s).IsPrerelease()
+}
+
+// Compare returns >0 if v is newer than o, <0 if older and 0 if equal.
+// Missing release components count as zero ("1.21" equals "1.21.0").
+func (v Version) Compare(o Version) int {
+	n := len(v.Release)
+	if len(o.Release) > n {
+		n = len(o.Release)
+	}
+	for i := 0; i < n; i++ {
+		a, b := component(v.Release, i), component(o.Release, i)
+		if a != b {
+			return a - b
+		}
+	}
+
+	if v.stage != o.stage {
+		return v.stage - o.stage
+	}
+	if c := compareIdentifiers(v.Pre, o.Pre); c != 0 {
+		return c
+	}
+	if c := compareIdentifiers(v.Qualifier, o.Qualifier); c != 0 {
+		return c
+	}
+	return compareIdentifiers(v.Build, o.Build)
+}
+
+func component(parts []int, i int) int {
+	if i < len(parts) {
+		return parts[i]
+	}
+	return 0
+}
+
+// compareIdentifiers compares identifier lists: numbers numerically and
+// below words, words alphabetically, and a shorter list first when one is
+// a prefix of the other ("rc" < "rc-1")
+func compareIdentifiers(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] == b[i] {
+			continue
+		}
+		aNum, bNum := isNumber(a[i]), isNumber(b[i])
+		switch {
+		case aNum && bNum:
+			x, _ := strconv.Atoi(a[i])
+			y, _ := strconv.Atoi(b[i])
+			if x != y {
+				return x - y
+			}
+		case aNum:
+			return -1
+		case bNum:
+			return 1
+		default:
+			// Stage words compare by stage ("m" < "rc"), others alphabetically
+			sa, okA := stages[a[i]]
+			sb, okB := stages[b[i]]
+			if okA && okB && sa != sb {
+				return sa - sb
+			}
+			return strings.Compare(a[i], b[i])
+		}
+	}
+	return len(a) - len(b)
+}
+
+// Compare compares two version strings, see Version.Compare
+func Compare(a, b string) int {
+	return Parse(a).Compare(Parse(b))
+}
+
+// Sort orders versions newest first
+func Sort(versions []string) {
+	parsed := make(map[string]Version, len(versions))
+	for _, v := range versions {
+		parsed[v] = Parse(v)
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return parsed[versions[i]].Compare(parsed[versions[j]]) > 0
+	})
+}
+
+// Releases returns the versions that are not prereleases, keeping order
+func Releases(versions []string) []string {
+	result := make([]string, 0, len(versions))
+	for _, v := range versions {
+		if !IsPrerelease(v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,90 @@
+package semver
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int // sign only
+	}{
+		{"3.3.0", "3.3.0-RC1", 1},
+		{"3.3.0-RC2", "3.3.0-RC1", 1},
+		{"3.3.0-RC1", "3.2.2", 1},
+		{"1.9.20", "1.9.20-Beta", 1},
+		{"1.9.20-RC", "1.9.20-Beta2", 1},
+		{"1.9.20-Beta2", "1.9.20-Beta", 1},
+		{"8.5", "8.5-rc-1", 1},
+		{"8.5-rc-2", "8.5-rc-1", 1},
+		{"8.5-rc-1", "8.5-milestone-3", 1},
+		{"8.5-milestone-1", "8.6-20231101220000+0000", -1},
+		{"2.13.0", "2.13.0-M5", 1},
+		{"1.23rc1", "1.22.5", 1},
+		{"1.23.0", "1.23rc1", 1},
+		{"1.23rc1", "1.23beta2", 1},
+		{"4.0.0-rc-1", "4.0.0-beta-5", 1},
+		{"4.0.0-beta-5", "4.0.0-alpha-13", 1},
+		{"21.0.2+13", "21.0.2+7", 1},
+		{"21.0.2", "21.0.10", -1},
+		{"21.0.2.13.1", "21.0.2", 1},
+		{"1.21", "1.21.0", 0},
+		{"v20.10.0", "20.10.0", 0},
+		{"3.0-final", "3.0", 0},
+	}
+	for _, tt := range tests {
+		got := Compare(tt.a, tt.b)
+		if sign(got) != tt.expected {
+			t.Errorf("Compare(%q, %q) = %d, expected sign %d", tt.a, tt.b, got, tt.expected)
+		}
+		if sign(Compare(tt.b, tt.a)) != -tt.expected {
+			t.Errorf("Compare(%q, %q) is not antisymmetric", tt.b, tt.a)
+		}
+	}
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	}
+	return 0
+}
+
+func TestIsPrerelease(t *testing.T) {
+	tests := map[string]bool{
+		"3.3.0":                   false,
+		"3.3.0-RC1":               true,
+		"1.9.20-Beta":             true,
+		"8.5-rc-1":                true,
+		"8.5-milestone-2":         true,
+		"2.13.0-M5":               true,
+		"1.23rc1":                 true,
+		"2.0.0-dev-12345":         true,
+		"8.6-20231101220000+0000": true,
+		"21.0.2+13":               false,
+		"21-tem":                  false,
+		"17.0.9-LTS":              false,
+	}
+	for v, expected := range tests {
+		if got := IsPrerelease(v); got != expected {
+			t.Errorf("IsPrerelease(%q) = %v, expected %v", v, got, expected)
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	versions := []string{"3.3.0-RC1", "3.2.2", "3.3.0", "3.10.0", "3.3.0-RC2", "3.3.1"}
+	Sort(versions)
+	expected := "3.10.0,3.3.1,3.3.0,3.3.0-RC2,3.3.0-RC1,3.2.2"
+	if got := strings.Join(versions, ","); got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+
+	if got := strings.Join(Releases(versions), ","); got != "3.10.0,3.3.1,3.3.0,3.2.2" {
+		t.Errorf("Releases: got %s", got)
+	}
+}
@@ -0,0 +1,110 @@
+package sources
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/azdren/verman/internal/semver"
+)
+
+// Channel aliases accepted wherever a version is: "latest" is the newest
+// release, "lts" (or nvm's "lts/*") the newest long-term support release and
+// "lts/<codename>" the newest release of a named LTS line ("lts/iron")
+const (
+	ChannelLatest = "latest"
+	ChannelLTS    = "lts"
+)
+
+// IsChannel reports whether a version is a channel alias
+func IsChannel(version string) bool {
+	v := strings.ToLower(version)
+	return v == ChannelLatest || v == ChannelLTS || strings.HasPrefix(v, ChannelLTS+"/")
+}
+
+// HasLTS reports whether the source's release list marks LTS releases
+func (s *Source) HasLTS() bool {
+	return s.Kind == KindFoojay || (s.ReleaseFields != nil && s.ReleaseFields.LTS != "")
+}
+
+// ChannelFilter returns a predicate matching the versions in a channel. An
+// LTS entry naming a whole release line (a Java major such as "21") matches
+// every version in it ("21.0.2").
+func (s *Source) ChannelFilter(channel string) (func(version string) bool, error) {
+	channel = strings.ToLower(channel)
+	if channel == ChannelLatest {
+		return func(string) bool { return true }, nil
+	}
+	if !s.HasLTS() {
+		return nil, fmt.Errorf("%s does not publish LTS releases; use a version or %q", s.DisplayName, ChannelLatest)
+	}
+
+	codename := strings.TrimPrefix(strings.TrimPrefix(channel, ChannelLTS), "/")
+	if codename == "*" {
+		codename = ""
+	}
+
+	releases, err := s.ListReleases()
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch available versions to resolve %s: %w", channel, err)
+	}
+
+	var lines []string
+	codenames := make(map[string]bool)
+	for _, r := range releases {
+		if r.LTS == "" {
+			continue
+		}
+		if r.LTS != "true" {
+			codenames[strings.ToLower(r.LTS)] = true
+		}
+		if codename == "" || strings.EqualFold(r.LTS, codename) {
+			lines = append(lines, r.Version)
+		}
+	}
+
+	if len(lines) == 0 {
+		if codename == "" {
+			return nil, fmt.Errorf("no LTS releases of %s are listed", s.DisplayName)
+		}
+		known := make([]string, 0, len(codenames))
+		for name := range codenames {
+			known = append(known, name)
+		}
+		sort.Strings(known)
+		return nil, fmt.Errorf("unknown %s LTS codename %q (known: %s)", s.DisplayName, codename, strings.Join(known, ", "))
+	}
+
+	return func(version string) bool {
+		for _, line := range lines {
+			if version == line || strings.HasPrefix(version, line+".") {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// resolveChannel returns the newest listed version in a channel
+func (s *Source) resolveChannel(channel string) (string, error) {
+	inChannel, err := s.ChannelFilter(channel)
+	if err != nil {
+		return "", err
+	}
+	versions, err := s.FetchVersions()
+	if err != nil {
+		return "", fmt.Errorf("could not fetch available versions to resolve %s: %w", channel, err)
+	}
+
+	var matches []string
+	for _, v := range versions {
+		if (s.ValidateVersion(v) || s.ValidateVersion("v"+v)) && inChannel(v) {
+			matches = append(matches, v)
+		}
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no %s versions found for %s", s.DisplayName, channel)
+	}
+	semver.Sort(matches)
+	return matches[0], nil
+}
@@ -0,0 +1,98 @@
+package sources
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// nodeIndex mimics nodejs.org/dist/index.json
+const nodeIndex = `[
+  {"version": "v22.1.0", "date": "2024-05-02", "lts": false},
+  {"version": "v20.12.2", "date": "2024-04-10", "lts": "Iron"},
+  {"version": "v20.11.0", "date": "2024-01-09", "lts": "Iron"},
+  {"version": "v18.20.2", "date": "2024-04-10", "lts": "Hydrogen"},
+  {"version": "v21.7.3", "date": "2024-04-10", "lts": false}
+]`
+
+func TestChannels(t *testing.T) {
+	withMetadataCache(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(nodeIndex))
+	}))
+	defer server.Close()
+
+	node := &Source{
+		Name:          "node-channels",
+		DisplayName:   "Node.js",
+		ReleasesURL:   server.URL + "/index.json",
+		VersionField:  "version",
+		ReleaseFields: &ReleaseFields{LTS: "lts", Date: "date"},
+	}
+
+	tests := map[string]string{
+		"latest":       "22.1.0",
+		"lts":          "20.12.2",
+		"lts/*":        "20.12.2",
+		"lts/hydrogen": "18.20.2",
+		"LTS/Iron":     "20.12.2",
+	}
+	for channel, want := range tests {
+		got, err := node.ResolveVersion(channel)
+		if err != nil {
+			t.Errorf("ResolveVersion(%q): %v", channel, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ResolveVersion(%q) = %s, want %s", channel, got, want)
+		}
+	}
+
+	_, err := node.ResolveVersion("lts/argon")
+	if err == nil || !strings.Contains(err.Error(), "known: hydrogen, iron") {
+		t.Errorf("Expected unknown codename error listing known ones, got %v", err)
+	}
+
+	releases, _ := node.ListReleases()
+	if len(releases) != 5 || releases[1].Date != "2024-04-10" {
+		t.Errorf("Expected release dates to be carried, got %+v", releases)
+	}
+}
+
+func TestChannelsWithoutLTS(t *testing.T) {
+	gradle := &Source{Name: "gradle-channels", DisplayName: "Gradle", StaticVersions: []string{"8.5", "8.7", "8.6"}}
+
+	if got, err := gradle.ResolveVersion("latest"); err != nil || got != "8.7" {
+		t.Errorf("ResolveVersion(latest) = %s, %v; want 8.7", got, err)
+	}
+	if _, err := gradle.ResolveVersion("lts"); err == nil {
+		t.Error("Expected an error for lts on a source without LTS releases")
+	}
+	if gradle.ValidateVersion("lts") {
+		t.Error("Expected lts to be invalid for Gradle")
+	}
+}
+
+func TestFoojayLTSChannel(t *testing.T) {
+	withMetadataCache(t)
+	server := newFoojayServer(t)
+	src := foojayTestSource("java-foojay-lts", server.URL)
+
+	// Temurin has a "latest" URL template, so a major version is enough
+	got, err := src.ResolveVersionWithDist("lts", "temurin")
+	if err != nil {
+		t.Fatalf("ResolveVersionWithDist(lts) failed: %v", err)
+	}
+	if got != "21" {
+		t.Errorf("Expected the newest LTS major 21, got %s", got)
+	}
+
+	inLTS, err := src.ChannelFilter("lts")
+	if err != nil {
+		t.Fatalf("ChannelFilter failed: %v", err)
+	}
+	if !inLTS("17.0.10") || inLTS("23.0.1") {
+		t.Error("Expected 17.x builds, but not 23.x, in the LTS channel")
+	}
+}
@@ -0,0 +1,155 @@
+package sources
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/azdren/verman/internal/semver"
+)
+
+// KindFoojay marks a source whose versions and builds come from the
+// Foojay Disco API (https://api.foojay.io), which indexes most JDK vendors
+const KindFoojay = "foojay"
+
+// foojayPackages caches the Disco package listing per distribution and platform.
+// The listing covers every major version, so one request serves all of them.
+var (
+	foojayMu       sync.Mutex
+	foojayPackages = make(map[string][]Release)
+)
+
+// foojayDistribution returns the Disco API identifier of a distribution
+func foojayDistribution(d *Distribution) string {
+	if d.APIName != "" {
+		return d.APIName
+	}
+	return d.Name
+}
+
+// fetchFoojayMajorVersions lists GA major versions from the Disco API,
+// flagging long-term support lines
+func fetchFoojayMajorVersions(releasesURL string) ([]Release, error) {
+	var resp struct {
+		Result []struct {
+			MajorVersion  int    `json:"major_version"`
+			TermOfSupport string `json:"term_of_support"`
+		} `json:"result"`
+	}
+	if err := getJSON(releasesURL, &resp); err != nil {
+		return nil, err
+	}
+
+	releases := make([]Release, 0, len(resp.Result))
+	for _, r := range resp.Result {
+		release := Release{Version: strconv.Itoa(r.MajorVersion)}
+		if strings.EqualFold(r.TermOfSupport, "LTS") {
+			release.LTS = "true"
+		}
+		releases = append(releases, release)
+	}
+	return releases, nil
+}
+
+// fetchFoojayReleases lists JDK packages of a distribution from the Disco API.
+// The listing carries only a metadata link; the direct download URI and
+// checksum are fetched for the chosen build by completeRelease.
+func fetchFoojayReleases(d *Distribution, baseURL, major string, p Platform) ([]Release, error) {
+	distro := foojayDistribution(d)
+	key := strings.Join([]string{baseURL, distro, p.String()}, "|")
+
+	foojayMu.Lock()
+	all, ok := foojayPackages[key]
+	foojayMu.Unlock()
+
+	if !ok {
+		var err error
+		all, err = fetchFoojayPackages(baseURL, distro, p)
+		if err != nil {
+			return nil, err
+		}
+		foojayMu.Lock()
+		foojayPackages[key] = all
+		foojayMu.Unlock()
+	}
+
+	var releases []Release
+	for _, r := range all {
+		if major == "" || strings.Split(r.Version, ".")[0] == major {
+			releases = append(releases, r)
+		}
+	}
+	return releases, nil
+}
+
+func fetchFoojayPackages(baseURL, distro string, p Platform) ([]Release, error) {
+	osName := p.OS
+	if osName == "darwin" {
+		osName = "macos"
+	}
+	archiveType := "tar.gz"
+	if p.OS == "windows" {
+		archiveType = "zip"
+	}
+
+	query := url.Values{
+		"distribution":          {distro},
+		"architecture":          {jdkArch(p.Arch)},
+		"operating_system":      {osName},
+		"archive_type":          {archiveType},
+		"package_type":          {"jdk"},
+		"release_status":        {"ga"},
+		"javafx_bundled":        {"false"},
+		"directly_downloadable": {"true"},
+	}
+	if p.OS == "linux" {
+		query.Set("lib_c_type", "glibc")
+	}
+
+	var resp struct {
+		Result []struct {
+			ID                  string `json:"id"`
+			JavaVersion         string `json:"java_version"`
+			DistributionVersion string `json:"distribution_version"`
+			Links               struct {
+				PkgInfoURI string `json:"pkg_info_uri"`
+			} `json:"links"`
+		} `json:"result"`
+	}
+	// Without a version filter the listing covers every major version
+	if err := getJSON(fmt.Sprintf("%s/disco/v3.0/packages?%s", baseURL, query.Encode()), &resp); err != nil {
+		return nil, err
+	}
+
+	var releases []Release
+	seen := make(map[string]bool)
+	for _, pkg := range resp.Result {
+		// "21.0.2+13" -> "21.0.2"; some vendors append "-LTS" or similar
+		version := pkg.JavaVersion
+		if idx := strings.IndexAny(version, "+-"); idx > 0 {
+			version = version[:idx]
+		}
+		if version == "" || seen[version] {
+			continue
+		}
+		seen[version] = true
+
+		infoURL := pkg.Links.PkgInfoURI
+		if infoURL == "" && pkg.ID != "" {
+			infoURL = fmt.Sprintf("%s/disco/v3.0/ids/%s", baseURL, pkg.ID)
+		}
+		releases = append(releases, Release{
+			Version: version,
+			Build:   pkg.DistributionVersion,
+			infoURL: infoURL,
+		})
+	}
+
+	sort.SliceStable(releases, func(i, j int) bool {
+		return semver.Compare(releases[i].Version, releases[j].Version) > 0
+	})
+	return releases, nil
+}
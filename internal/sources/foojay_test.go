@@ -0,0 +1,163 @@
+package sources
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newFoojayServer replays recorded Disco API responses from testdata/foojay.
+// Package listings are keyed by the "distribution" query parameter.
+func newFoojayServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var file string
+		switch {
+		case r.URL.Path == "/disco/v3.0/major_versions":
+			file = "major_versions.json"
+		case r.URL.Path == "/disco/v3.0/packages":
+			file = "packages_" + r.URL.Query().Get("distribution") + ".json"
+		case strings.HasPrefix(r.URL.Path, "/disco/v3.0/ids/"):
+			file = "ids_" + strings.TrimPrefix(r.URL.Path, "/disco/v3.0/ids/") + ".json"
+		}
+
+		data, err := os.ReadFile(filepath.Join("testdata", "foojay", file))
+		if file == "" || err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(strings.ReplaceAll(string(data), "{{server}}", server.URL)))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// foojayTestSource builds a Disco-backed Java source pointing at the stand-in server
+func foojayTestSource(name, baseURL string) *Source {
+	return &Source{
+		Kind:        KindFoojay,
+		Name:        name,
+		DisplayName: "Java",
+		ReleasesURL: baseURL + "/disco/v3.0/major_versions?ga=true",
+		APIURL:      baseURL,
+		DefaultDist: "temurin",
+		Distributions: map[string]*Distribution{
+			"temurin":    {Name: "temurin", DisplayName: "Eclipse Temurin", DownloadURL: "https://latest/{majorVersion}"},
+			"liberica":   {Name: "liberica", DisplayName: "BellSoft Liberica"},
+			"sapmachine": {Name: "sapmachine", DisplayName: "SapMachine", APIName: "sap_machine"},
+		},
+	}
+}
+
+func TestFoojayFetchVersions(t *testing.T) {
+	server := newFoojayServer(t)
+	src := foojayTestSource("java-foojay-versions", server.URL)
+
+	versions, err := src.FetchVersions()
+	if err != nil {
+		t.Fatalf("FetchVersions failed: %v", err)
+	}
+
+	expected := []string{"23", "21", "17"}
+	if len(versions) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, versions)
+	}
+	for i, v := range expected {
+		if versions[i] != v {
+			t.Errorf("version[%d]: expected %s, got %s", i, v, versions[i])
+		}
+	}
+}
+
+func TestFoojayFetchReleases(t *testing.T) {
+	server := newFoojayServer(t)
+	src := foojayTestSource("java-foojay-releases", server.URL)
+
+	if !src.HasReleaseAPI("librca") {
+		t.Fatal("Distributions of a foojay source should default to the Disco API")
+	}
+
+	releases, err := src.FetchReleases("librca", "21")
+	if err != nil {
+		t.Fatalf("FetchReleases failed: %v", err)
+	}
+	if len(releases) != 2 || releases[0].Version != "21.0.2" || releases[1].Version != "21.0.1" {
+		t.Fatalf("Unexpected releases: %+v", releases)
+	}
+
+	releases, err = src.FetchReleases("liberica", "17")
+	if err != nil {
+		t.Fatalf("FetchReleases failed: %v", err)
+	}
+	if len(releases) != 1 || releases[0].Version != "17.0.10" {
+		t.Errorf("Unexpected Java 17 releases: %+v", releases)
+	}
+
+	// Unknown distribution in the stand-in answers 404
+	if _, err := src.FetchReleases("sapmchn", "21"); err == nil {
+		t.Error("Expected error for a distribution the API does not know")
+	}
+}
+
+func TestFoojayResolveAndFindRelease(t *testing.T) {
+	server := newFoojayServer(t)
+	src := foojayTestSource("java-foojay-find", server.URL)
+
+	// Without a "latest" URL template, a bare major resolves to a concrete build
+	resolved, err := src.ResolveVersionWithDist("21", "librca")
+	if err != nil {
+		t.Fatalf("ResolveVersionWithDist failed: %v", err)
+	}
+	if resolved != "21.0.2" {
+		t.Errorf("Expected 21.0.2, got %s", resolved)
+	}
+
+	release, err := src.FindRelease("21.0.2", "librca")
+	if err != nil {
+		t.Fatalf("FindRelease failed: %v", err)
+	}
+	if !strings.HasSuffix(release.URL, "bellsoft-jdk21.0.2+14-windows-amd64.zip") {
+		t.Errorf("Expected direct download URI, got %s", release.URL)
+	}
+	if len(release.Checksum) != 64 {
+		t.Errorf("Expected SHA256 checksum from package info, got %q", release.Checksum)
+	}
+
+	// Non-SHA256 checksums are not usable for verification
+	release, err = src.FindRelease("21.0.1", "librca")
+	if err != nil {
+		t.Fatalf("FindRelease failed: %v", err)
+	}
+	if release.Checksum != "" {
+		t.Errorf("Expected SHA1 checksum to be ignored, got %q", release.Checksum)
+	}
+
+	// Bare major with a "latest" URL template keeps using it
+	release, err = src.FindRelease("21", "temurin")
+	if err != nil || release != nil {
+		t.Errorf("Expected latest-URL fallback for temurin, got %+v, %v", release, err)
+	}
+}
+
+func TestJavaDefinitionUsesFoojay(t *testing.T) {
+	java, ok := Get("java")
+	if !ok {
+		t.Fatal("Java source not found")
+	}
+
+	if java.Kind != KindFoojay {
+		t.Errorf("Java source should be a foojay source, got kind %q", java.Kind)
+	}
+
+	for _, dist := range []string{"temurin", "corretto", "zulu", "liberica", "microsoft", "sapmachine", "graalce", "semeru", "oracle"} {
+		if !java.HasReleaseAPI(dist) {
+			t.Errorf("Java distribution %s should resolve builds through the Disco API", dist)
+		}
+	}
+}
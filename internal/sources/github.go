@@ -0,0 +1,205 @@
+package sources
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// KindGitHub marks a source whose releasesUrl is a GitHub
+// /repos/{owner}/{repo}/releases endpoint
+const KindGitHub = "github"
+
+// githubMaxPages bounds pagination (100 releases per page)
+const githubMaxPages = 20
+
+// githubAPIHost is the only host the token goes to besides a configured
+// GitHub Enterprise host
+const githubAPIHost = "api.github.com"
+
+var (
+	githubMu    sync.Mutex
+	githubToken string // Token from config; GITHUB_TOKEN takes precedence
+	githubHost  string // GitHub Enterprise API host from config
+)
+
+// SetGitHubToken sets the token used for GitHub API requests when the
+// GITHUB_TOKEN environment variable is not set
+func SetGitHubToken(token string) {
+	githubMu.Lock()
+	defer githubMu.Unlock()
+	githubToken = token
+}
+
+// SetGitHubHost sets a GitHub Enterprise Server API host ("ghe.example.com"
+// or a URL) that may receive the token besides api.github.com
+func SetGitHubHost(host string) {
+	if u, err := url.Parse(host); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	githubMu.Lock()
+	defer githubMu.Unlock()
+	githubHost = strings.ToLower(strings.TrimSuffix(host, "/"))
+}
+
+// gitHubTokenFor reports whether a request to rawURL may carry the token:
+// only HTTPS requests to api.github.com and requests to the configured
+// GitHub Enterprise host do. Source definitions and Link headers can point
+// anywhere, so every page is checked.
+func gitHubTokenFor(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Host)
+	if host == githubAPIHost {
+		return u.Scheme == "https"
+	}
+	githubMu.Lock()
+	defer githubMu.Unlock()
+	return githubHost != "" && host == githubHost
+}
+
+// gitHubToken returns the token to authenticate GitHub API requests with
+func gitHubToken() string {
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		return token
+	}
+	githubMu.Lock()
+	defer githubMu.Unlock()
+	return githubToken
+}
+
+// RateLimitError reports an exhausted GitHub API rate limit
+type RateLimitError struct {
+	Reset         time.Time // When the limit resets, zero if unknown
+	Authenticated bool
+}
+
+func (e *RateLimitError) Error() string {
+	msg := "GitHub API rate limit exceeded"
+	if !e.Reset.IsZero() {
+		wait := time.Until(e.Reset).Round(time.Minute)
+		if wait < time.Minute {
+			wait = time.Minute
+		}
+		msg += fmt.Sprintf("; resets at %s (in %s)", e.Reset.Local().Format("15:04"), wait)
+	}
+	if !e.Authenticated {
+		msg += "; set GITHUB_TOKEN (or github_token in config.json) for a higher limit"
+	}
+	return msg
+}
+
+// githubRelease is one entry of the GitHub releases API, keeping all fields
+// so versionField can name any of them
+type githubRelease map[string]interface{}
+
+func (r githubRelease) flag(name string) bool {
+	b, _ := r[name].(bool)
+	return b
+}
+
+// fetchGitHubReleases lists every release of a repository, following Link
+// pagination. Drafts are always skipped, prereleases unless asked for.
+func fetchGitHubReleases(releasesURL string, includePrereleases bool) ([]githubRelease, error) {
+	header := http.Header{}
+	header.Set("Accept", "application/vnd.github+json")
+	header.Set("X-GitHub-Api-Version", "2022-11-28")
+	token := gitHubToken()
+
+	var releases []githubRelease
+	next := githubFirstPage(releasesURL)
+	for page := 0; next != "" && page < githubMaxPages; page++ {
+		pageHeader := header.Clone()
+		authenticated := token != "" && gitHubTokenFor(next)
+		if authenticated {
+			pageHeader.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := fetchResponse(next, pageHeader)
+		if err != nil {
+			return nil, githubError(err, authenticated)
+		}
+
+		var pageReleases []githubRelease
+		if err := json.Unmarshal([]byte(resp.Body), &pageReleases); err != nil {
+			return nil, fmt.Errorf("unexpected GitHub response from %s: %w", next, err)
+		}
+		for _, r := range pageReleases {
+			if r.flag("draft") || (r.flag("prerelease") && !includePrereleases) {
+				continue
+			}
+			releases = append(releases, r)
+		}
+		next = resp.Next
+	}
+	if next != "" {
+		fmt.Fprintf(os.Stderr, "Warning: stopped listing %s after %d pages; versions older than the %d releases listed are not found\n",
+			releasesURL, githubMaxPages, len(releases))
+	}
+	return releases, nil
+}
+
+// githubFirstPage asks for the largest page size GitHub allows
+func githubFirstPage(releasesURL string) string {
+	u, err := url.Parse(releasesURL)
+	if err != nil {
+		return releasesURL
+	}
+	q := u.Query()
+	if q.Get("per_page") == "" {
+		q.Set("per_page", "100")
+		u.RawQuery = q.Encode()
+	}
+	return u.String()
+}
+
+// githubError turns rate-limit and auth failures into actionable errors
+func githubError(err error, authenticated bool) error {
+	var statusErr *httpStatusError
+	if !errors.As(err, &statusErr) {
+		return err
+	}
+
+	h := statusErr.Header
+	switch {
+	case (statusErr.Status == http.StatusForbidden || statusErr.Status == http.StatusTooManyRequests) &&
+		(h.Get("X-RateLimit-Remaining") == "0" || h.Get("Retry-After") != ""):
+		rl := &RateLimitError{Authenticated: authenticated}
+		if reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			rl.Reset = time.Unix(reset, 0)
+		} else if secs, err := strconv.Atoi(h.Get("Retry-After")); err == nil {
+			rl.Reset = time.Now().Add(time.Duration(secs) * time.Second)
+		}
+		return rl
+	case statusErr.Status == http.StatusUnauthorized && authenticated:
+		return fmt.Errorf("GitHub rejected the token (check GITHUB_TOKEN or github_token in config.json): %w", err)
+	}
+	return err
+}
+
+// fetchGitHubVersions lists versions from a GitHub releases endpoint,
+// reading the version from versionField (usually "tag_name") and the
+// release date from published_at
+func (s *Source) fetchGitHubVersions() ([]Release, error) {
+	releases, err := fetchGitHubReleases(s.ReleasesURL, s.IncludesPrereleases())
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []Release
+	for _, r := range releases {
+		if v := s.extractVersion(map[string]interface{}(r)); v != "" {
+			published, _ := r["published_at"].(string)
+			versions = append(versions, Release{Version: v, Date: releaseDate(published)})
+		}
+	}
+	return versions, nil
+}
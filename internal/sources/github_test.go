@@ -0,0 +1,216 @@
+package sources
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newGitHubServer serves a releases endpoint split into pages of two,
+// linked with a GitHub-style Link header
+func newGitHubServer(t *testing.T, tags []string, check func(r *http.Request)) *httptest.Server {
+	t.Helper()
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if check != nil {
+			check(r)
+		}
+		page := 1
+		_, _ = fmt.Sscanf(r.URL.Query().Get("page"), "%d", &page)
+
+		start, end := (page-1)*2, page*2
+		if end > len(tags) {
+			end = len(tags)
+		}
+		if end < len(tags) {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/scala/scala/releases?per_page=100&page=%d>; rel="next", <%s/repos/scala/scala/releases?page=9>; rel="last"`,
+				server.URL, page+1, server.URL))
+		}
+
+		var items []string
+		for _, tag := range tags[start:end] {
+			draft := strings.HasSuffix(tag, "-draft")
+			pre := strings.Contains(tag, "-RC")
+			items = append(items, fmt.Sprintf(`{"tag_name":%q,"draft":%t,"prerelease":%t}`, tag, draft, pre))
+		}
+		_, _ = w.Write([]byte("[" + strings.Join(items, ",") + "]"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func githubTestSource(baseURL string) *Source {
+	return &Source{
+		Kind:         KindGitHub,
+		Name:         "scala-github",
+		DisplayName:  "Scala 2",
+		ReleasesURL:  baseURL + "/repos/scala/scala/releases",
+		VersionField: "tag_name",
+	}
+}
+
+func TestGitHubPagination(t *testing.T) {
+	tags := []string{"v2.13.14", "v2.13.13", "v2.13.15-RC1", "v2.12.19", "v2.12.20-draft", "v2.12.18"}
+	server := newGitHubServer(t, tags, func(r *http.Request) {
+		if r.URL.Query().Get("per_page") != "100" {
+			t.Errorf("Expected per_page=100, got %q", r.URL.RawQuery)
+		}
+	})
+	src := githubTestSource(server.URL)
+
+	versions, err := src.FetchVersions()
+	if err != nil {
+		t.Fatalf("FetchVersions failed: %v", err)
+	}
+	expected := []string{"2.13.14", "2.13.13", "2.12.19", "2.12.18"}
+	if strings.Join(versions, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v (no drafts or prereleases), got %v", expected, versions)
+	}
+
+	// Versions on later pages resolve too
+	resolved, err := src.ResolveVersion("2.12")
+	if err != nil || resolved != "2.12.19" {
+		t.Errorf("ResolveVersion(2.12): expected 2.12.19, got %q, %v", resolved, err)
+	}
+
+	src.IncludePrereleases = true
+	versions, _ = src.FetchVersions()
+	if len(versions) != 5 {
+		t.Errorf("Expected prerelease to be included on request, got %v", versions)
+	}
+}
+
+func TestGitHubToken(t *testing.T) {
+	var auth string
+	server := newGitHubServer(t, []string{"v1.9.0"}, func(r *http.Request) {
+		auth = r.Header.Get("Authorization")
+	})
+	src := githubTestSource(server.URL)
+
+	t.Setenv("GITHUB_TOKEN", "")
+	SetGitHubToken("from-config")
+	defer SetGitHubToken("")
+
+	// Hosts other than api.github.com get no token unless configured
+	SetGitHubHost("")
+	if _, err := src.FetchVersions(); err != nil {
+		t.Fatalf("FetchVersions failed: %v", err)
+	}
+	if auth != "" {
+		t.Errorf("Expected no token for an unconfigured host, got %q", auth)
+	}
+
+	SetGitHubHost(server.URL)
+	defer SetGitHubHost("")
+	if _, err := src.FetchVersions(); err != nil {
+		t.Fatalf("FetchVersions failed: %v", err)
+	}
+	if auth != "Bearer from-config" {
+		t.Errorf("Expected configured token, got %q", auth)
+	}
+
+	t.Setenv("GITHUB_TOKEN", "from-env")
+	if _, err := src.FetchVersions(); err != nil {
+		t.Fatalf("FetchVersions failed: %v", err)
+	}
+	if auth != "Bearer from-env" {
+		t.Errorf("Expected GITHUB_TOKEN to take precedence, got %q", auth)
+	}
+}
+
+func TestGitHubTokenNotSentToOtherHosts(t *testing.T) {
+	var otherAuth []string
+	other := newGitHubServer(t, []string{"v1.8.0"}, func(r *http.Request) {
+		otherAuth = append(otherAuth, r.Header.Get("Authorization"))
+	})
+	// A trusted first page whose Link header points at another host
+	trusted := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", fmt.Sprintf(`<%s/repos/scala/scala/releases?page=1>; rel="next"`, other.URL))
+		_, _ = w.Write([]byte(`[{"tag_name":"v1.9.0"}]`))
+	}))
+	defer trusted.Close()
+
+	t.Setenv("GITHUB_TOKEN", "secret")
+	SetGitHubHost(trusted.URL)
+	defer SetGitHubHost("")
+
+	versions, err := githubTestSource(trusted.URL).FetchVersions()
+	if err != nil {
+		t.Fatalf("FetchVersions failed: %v", err)
+	}
+	if len(versions) != 2 {
+		t.Errorf("Expected releases from both pages, got %v", versions)
+	}
+	if len(otherAuth) != 1 || otherAuth[0] != "" {
+		t.Errorf("Expected the other host to get no token, got %q", otherAuth)
+	}
+
+	for rawURL, want := range map[string]bool{
+		"https://api.github.com/repos/a/b/releases": true,
+		"http://api.github.com/repos/a/b/releases":  false,
+		"https://api.github.com.evil.example/repos": false,
+		"https://mirror.example/repos/a/b/releases": false,
+	} {
+		if got := gitHubTokenFor(rawURL); got != want {
+			t.Errorf("gitHubTokenFor(%q) = %v, want %v", rawURL, got, want)
+		}
+	}
+}
+
+func TestGitHubRateLimit(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	reset := time.Now().Add(23 * time.Minute)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprint(reset.Unix()))
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"message":"API rate limit exceeded"}`))
+	}))
+	defer server.Close()
+
+	_, err := githubTestSource(server.URL).FetchVersions()
+	var rl *RateLimitError
+	if !errors.As(err, &rl) {
+		t.Fatalf("Expected RateLimitError, got %v", err)
+	}
+	if rl.Reset.Unix() != reset.Unix() {
+		t.Errorf("Expected reset %v, got %v", reset, rl.Reset)
+	}
+	if !strings.Contains(err.Error(), "resets at") || !strings.Contains(err.Error(), "GITHUB_TOKEN") {
+		t.Errorf("Expected reset time and token hint in %q", err.Error())
+	}
+}
+
+func TestNextLink(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{`<https://api.github.com/r?page=2>; rel="next", <https://api.github.com/r?page=5>; rel="last"`, "https://api.github.com/r?page=2"},
+		{`<https://api.github.com/r?page=1>; rel="prev", <https://api.github.com/r?page=1>; rel="first"`, ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := nextLink(tt.header); got != tt.expected {
+			t.Errorf("nextLink(%q): expected %q, got %q", tt.header, tt.expected, got)
+		}
+	}
+}
+
+func TestGitHubDefinitions(t *testing.T) {
+	for _, name := range []string{"scala", "scala3", "kotlin", "sbt"} {
+		src, ok := Get(name)
+		if !ok {
+			t.Errorf("Source %s not found", name)
+			continue
+		}
+		if src.Kind != KindGitHub {
+			t.Errorf("Source %s should use the GitHub fetcher, got kind %q", name, src.Kind)
+		}
+	}
+}
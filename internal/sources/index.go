@@ -0,0 +1,104 @@
+package sources
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/azdren/verman/internal/semver"
+)
+
+// KindIndex marks a source whose releasesUrl is an HTML directory listing
+// (Apache or nginx autoindex) rather than a JSON API
+const KindIndex = "index"
+
+// defaultLinkRegex matches version-named subdirectories, e.g. "3.9.6/"
+const defaultLinkRegex = `^v?(\d+(?:\.\d+)*)/$`
+
+// hrefRegex finds link targets in an HTML page
+var hrefRegex = regexp.MustCompile(`(?i)<a\s[^>]*?href\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+
+// fetchIndexVersions scrapes versions from a directory listing. linkRegex
+// is matched against the last path segment of every link (keeping a
+// trailing "/" for directories); its first group, or the whole match, is
+// the version.
+func (s *Source) fetchIndexVersions() ([]string, error) {
+	pattern := s.LinkRegex
+	if pattern == "" {
+		pattern = defaultLinkRegex
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid linkRegex: %w", s.Name, err)
+	}
+
+	body, err := fetchMetadata(s.ReleasesURL, "text/html")
+	if err != nil {
+		return nil, err
+	}
+	base, err := url.Parse(s.ReleasesURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	seen := make(map[string]bool)
+	for _, link := range indexLinks(string(body), base) {
+		m := re.FindStringSubmatch(link)
+		if m == nil {
+			continue
+		}
+		ver := m[0]
+		if len(m) > 1 && m[1] != "" {
+			ver = m[1]
+		}
+		if v := s.extractVersion(ver); v != "" && !seen[v] {
+			seen[v] = true
+			versions = append(versions, v)
+		}
+	}
+
+	// Listings are sorted by name; report newest first like the APIs do
+	semver.Sort(versions)
+	return versions, nil
+}
+
+// indexLinks returns the last path segment of every link on a listing page
+// that points inside the listed directory. Parent links, sort links
+// ("?C=N;O=D") and links to other hosts are skipped.
+func indexLinks(page string, base *url.URL) []string {
+	dir := base.Path
+	if !strings.HasSuffix(dir, "/") {
+		dir = path.Dir(dir) + "/"
+	}
+
+	var links []string
+	for _, m := range hrefRegex.FindAllStringSubmatch(page, -1) {
+		href := html.UnescapeString(m[1] + m[2] + m[3]) // "&amp;", "&#45;" in attribute values
+		if href == "" || strings.HasPrefix(href, "?") || strings.HasPrefix(href, "#") {
+			continue
+		}
+		u, err := base.Parse(href)
+		if err != nil || u.Host != base.Host || !strings.HasPrefix(u.Path, dir) {
+			continue
+		}
+
+		rel := strings.TrimPrefix(u.Path, dir)
+		if rel == "" {
+			continue
+		}
+		// Only direct children: "3.9.6/" or "apache-ant-1.10.14-bin.zip"
+		name := strings.TrimSuffix(rel, "/")
+		if strings.Contains(name, "/") {
+			continue
+		}
+		if strings.HasSuffix(rel, "/") {
+			name += "/"
+		}
+		links = append(links, name)
+	}
+	return links
+}
@@ -0,0 +1,94 @@
+package sources
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// apacheIndex mimics an Apache autoindex page
+const apacheIndex = `<html><head><title>Index of /dist/ant/binaries</title></head><body>
+<h1>Index of /dist/ant/binaries</h1>
+<pre><a href="?C=N;O=D">Name</a> <a href="?C=M;O=A">Last modified</a>
+<a href="/dist/ant/">Parent Directory</a>
+<a href="apache-ant-1.9.16-bin.zip">apache-ant-1.9.16-bin.zip</a>       2021-07-07 13:06  8.3M
+<a href="apache-ant-1.9.16-bin.zip.asc">apache-ant-1.9.16-bin.zip.asc</a>   2021-07-07 13:06  833
+<a href="apache-ant-1.10.13-bin.tar.gz">apache-ant-1.10.13-bin.tar.gz</a>   2023-01-10 09:10  6.0M
+<a href="apache-ant-1.10.13-bin.zip">apache-ant-1.10.13-bin.zip</a>     2023-01-10 09:10  8.4M
+<a href='/dist/ant/binaries/apache-ant-1.10.14-bin.zip'>apache-ant-1.10.14-bin.zip</a>     2023-08-16 12:00  8.4M
+<a href="https://mirror.example.com/apache-ant-9.9.9-bin.zip">elsewhere</a>
+</pre></body></html>`
+
+// nginxIndex mimics an nginx autoindex page of version directories
+const nginxIndex = `<html><head><title>Index of /tools/</title></head><body>
+<h1>Index of /tools/</h1><hr><pre><a href="../">../</a>
+<a href="2.0.1/">2.0.1/</a>                                             01-Feb-2024 10:00       -
+<a href="2.10.0/">2.10.0/</a>                                            01-Mar-2024 10:00       -
+<a href="v2.9.3/">v2.9.3/</a>                                            01-Jan-2024 10:00       -
+<a href="latest/">latest/</a>                                            01-Mar-2024 10:00       -
+<a href="README.txt">README.txt</a>                                       01-Mar-2024 10:00     120
+</pre><hr></body></html>`
+
+func TestIndexVersions(t *testing.T) {
+	withMetadataCache(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/dist/ant/binaries/":
+			_, _ = w.Write([]byte(apacheIndex))
+		case "/tools/":
+			_, _ = w.Write([]byte(nginxIndex))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	ant := &Source{
+		Kind:        KindIndex,
+		Name:        "ant-index",
+		ReleasesURL: server.URL + "/dist/ant/binaries/",
+		LinkRegex:   `^apache-ant-(\d+\.\d+\.\d+)-bin\.zip$`,
+	}
+	versions, err := ant.FetchVersions()
+	if err != nil {
+		t.Fatalf("FetchVersions failed: %v", err)
+	}
+	expected := []string{"1.10.14", "1.10.13", "1.9.16"}
+	if strings.Join(versions, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, versions)
+	}
+
+	// Default regex picks version directories and strips a "v" prefix
+	tools := &Source{Kind: KindIndex, Name: "tools-index", ReleasesURL: server.URL + "/tools/"}
+	versions, err = tools.FetchVersions()
+	if err != nil {
+		t.Fatalf("FetchVersions failed: %v", err)
+	}
+	expected = []string{"2.10.0", "2.9.3", "2.0.1"}
+	if strings.Join(versions, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, versions)
+	}
+
+	resolved, err := tools.ResolveVersion("2.9")
+	if err != nil || resolved != "2.9.3" {
+		t.Errorf("ResolveVersion(2.9): expected 2.9.3, got %q, %v", resolved, err)
+	}
+}
+
+func TestIndexLinks(t *testing.T) {
+	base, _ := url.Parse("https://archive.example.org/dist/tool/index.html")
+	page := `<a href="1.0/">1.0/</a> <a href="1.0/tool.zip">nested</a> <a href="/dist/">up</a> <a href=2.0/>2.0/</a>`
+	got := strings.Join(indexLinks(page, base), ",")
+	if got != "1.0/,2.0/" {
+		t.Errorf("Expected only direct children, got %s", got)
+	}
+
+	// Attribute values are HTML-escaped
+	page = `<a href="tool&#45;1.0.zip">tool-1.0.zip</a> <a href="a&amp;b/">a&amp;b/</a>`
+	got = strings.Join(indexLinks(page, base), ",")
+	if got != "tool-1.0.zip,a&b/" {
+		t.Errorf("Expected unescaped links, got %s", got)
+	}
+}
@@ -0,0 +1,467 @@
+package sources
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a compiled JSONPath-style expression over decoded JSON
+// (map[string]interface{}, []interface{}, string, float64, bool, nil).
+//
+// Supported syntax:
+//
+//	$ or @           the root (optional; a path may also start with a name)
+//	.name ['name']   object member
+//	[2] [-1]         array element, negative indexes count from the end
+//	.* [*]           every member or element
+//	..name ..*       recursive descent
+//	[?(<filter>)]    elements for which the filter holds, e.g.
+//	                 [?(@.stable==true)], [?(@.os=='linux' && @.size>0)],
+//	                 [?(@.lts)] (member present and not false/null/"")
+type jsonPath struct {
+	steps []pathStep
+}
+
+type stepKind int
+
+const (
+	stepChild stepKind = iota
+	stepIndex
+	stepWildcard
+	stepRecursive // name == "" matches every descendant
+	stepFilter
+)
+
+type pathStep struct {
+	kind   stepKind
+	name   string
+	index  int
+	filter *pathFilter
+}
+
+// compileJSONPath parses a path expression
+func compileJSONPath(expr string) (*jsonPath, error) {
+	p := &jsonPath{}
+	s := strings.TrimSpace(expr)
+	if strings.HasPrefix(s, "$") || strings.HasPrefix(s, "@") {
+		s = s[1:]
+	} else if s != "" && s[0] != '.' && s[0] != '[' {
+		// Relative path starting with a member name, e.g. "files[0].sha256"
+		s = "." + s
+	}
+
+	for s != "" {
+		var step pathStep
+		var err error
+		switch {
+		case strings.HasPrefix(s, ".."):
+			s = s[2:]
+			step.kind = stepRecursive
+			if strings.HasPrefix(s, "*") {
+				s = s[1:]
+			} else {
+				step.name, s = readName(s)
+				if step.name == "" {
+					return nil, fmt.Errorf("invalid path %q: expected a name after '..'", expr)
+				}
+			}
+		case s[0] == '.':
+			s = s[1:]
+			if strings.HasPrefix(s, "*") {
+				step.kind = stepWildcard
+				s = s[1:]
+				break
+			}
+			step.kind = stepChild
+			step.name, s = readName(s)
+			if step.name == "" {
+				return nil, fmt.Errorf("invalid path %q: expected a name after '.'", expr)
+			}
+		case s[0] == '[':
+			step, s, err = parseBracket(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid path %q: %w", expr, err)
+			}
+		default:
+			return nil, fmt.Errorf("invalid path %q: unexpected %q", expr, s)
+		}
+		p.steps = append(p.steps, step)
+	}
+	return p, nil
+}
+
+// readName reads a member name up to the next '.' or '['
+func readName(s string) (string, string) {
+	end := strings.IndexAny(s, ".[")
+	if end < 0 {
+		end = len(s)
+	}
+	return strings.TrimSpace(s[:end]), s[end:]
+}
+
+// parseBracket parses one [...] step
+func parseBracket(s string) (pathStep, string, error) {
+	end := matchingBracket(s)
+	if end < 0 {
+		return pathStep{}, "", fmt.Errorf("unterminated '['")
+	}
+	inner, rest := strings.TrimSpace(s[1:end]), s[end+1:]
+
+	switch {
+	case inner == "*":
+		return pathStep{kind: stepWildcard}, rest, nil
+	case strings.HasPrefix(inner, "?"):
+		body := strings.TrimSpace(inner[1:])
+		if strings.HasPrefix(body, "(") && strings.HasSuffix(body, ")") {
+			body = body[1 : len(body)-1]
+		}
+		f, err := parseFilter(body)
+		if err != nil {
+			return pathStep{}, "", err
+		}
+		return pathStep{kind: stepFilter, filter: f}, rest, nil
+	case isQuoted(inner):
+		return pathStep{kind: stepChild, name: inner[1 : len(inner)-1]}, rest, nil
+	}
+
+	n, err := strconv.Atoi(inner)
+	if err != nil {
+		return pathStep{}, "", fmt.Errorf("unsupported selector [%s]", inner)
+	}
+	return pathStep{kind: stepIndex, index: n}, rest, nil
+}
+
+// matchingBracket returns the index of the ']' closing s[0], skipping
+// quoted strings and nested brackets
+func matchingBracket(s string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isQuoted(s string) bool {
+	return len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0]
+}
+
+// eval returns every value the path selects from root
+func (p *jsonPath) eval(root interface{}) []interface{} {
+	current := []interface{}{root}
+	for _, step := range p.steps {
+		var next []interface{}
+		for _, node := range current {
+			next = append(next, step.apply(node)...)
+		}
+		current = next
+	}
+	return current
+}
+
+// first returns the first value the path selects, nil if none
+func (p *jsonPath) first(root interface{}) interface{} {
+	if values := p.eval(root); len(values) > 0 {
+		return values[0]
+	}
+	return nil
+}
+
+func (step pathStep) apply(node interface{}) []interface{} {
+	switch step.kind {
+	case stepChild:
+		if obj, ok := node.(map[string]interface{}); ok {
+			if v, ok := obj[step.name]; ok {
+				return []interface{}{v}
+			}
+		}
+	case stepIndex:
+		if arr, ok := node.([]interface{}); ok {
+			i := step.index
+			if i < 0 {
+				i += len(arr)
+			}
+			if i >= 0 && i < len(arr) {
+				return []interface{}{arr[i]}
+			}
+		}
+	case stepWildcard:
+		return children(node)
+	case stepRecursive:
+		var result []interface{}
+		collectDescendants(node, step.name, &result)
+		return result
+	case stepFilter:
+		var result []interface{}
+		for _, child := range children(node) {
+			if step.filter.matches(child) {
+				result = append(result, child)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// children returns the elements of an array or the values of an object.
+// Object values are ordered by key so results are deterministic.
+func children(node interface{}) []interface{} {
+	switch v := node.(type) {
+	case []interface{}:
+		return v
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		result := make([]interface{}, 0, len(v))
+		for _, k := range keys {
+			result = append(result, v[k])
+		}
+		return result
+	}
+	return nil
+}
+
+func collectDescendants(node interface{}, name string, result *[]interface{}) {
+	if obj, ok := node.(map[string]interface{}); ok && name != "" {
+		if v, ok := obj[name]; ok {
+			*result = append(*result, v)
+		}
+	}
+	for _, child := range children(node) {
+		if name == "" {
+			*result = append(*result, child)
+		}
+		collectDescendants(child, name, result)
+	}
+}
+
+// pathFilter is a filter expression: comparisons joined by && and ||
+// (&& binds tighter)
+type pathFilter struct {
+	any [][]filterTerm // OR of ANDs
+}
+
+type filterTerm struct {
+	path    *jsonPath
+	op      string // "" tests for a truthy value
+	literal interface{}
+}
+
+var filterOps = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+func parseFilter(expr string) (*pathFilter, error) {
+	f := &pathFilter{}
+	for _, alt := range splitTopLevel(expr, "||") {
+		var terms []filterTerm
+		for _, cond := range splitTopLevel(alt, "&&") {
+			term, err := parseTerm(strings.TrimSpace(cond))
+			if err != nil {
+				return nil, err
+			}
+			terms = append(terms, term)
+		}
+		f.any = append(f.any, terms)
+	}
+	return f, nil
+}
+
+func parseTerm(cond string) (filterTerm, error) {
+	if !strings.HasPrefix(cond, "@") {
+		return filterTerm{}, fmt.Errorf("filter condition %q must start with @", cond)
+	}
+
+	lhs, op, rhs := cond, "", ""
+	for i := 0; i < len(cond); i++ {
+		if cond[i] == '\'' || cond[i] == '"' || cond[i] == '[' {
+			// Skip quoted names and brackets inside the path
+			if cond[i] == '[' {
+				if end := matchingBracket(cond[i:]); end > 0 {
+					i += end
+				}
+				continue
+			}
+			if end := strings.IndexByte(cond[i+1:], cond[i]); end >= 0 {
+				i += end + 1
+			}
+			continue
+		}
+		for _, candidate := range filterOps {
+			if strings.HasPrefix(cond[i:], candidate) {
+				lhs, op, rhs = cond[:i], candidate, cond[i+len(candidate):]
+				break
+			}
+		}
+		if op != "" {
+			break
+		}
+	}
+
+	path, err := compileJSONPath(strings.TrimSpace(lhs))
+	if err != nil {
+		return filterTerm{}, err
+	}
+	term := filterTerm{path: path, op: op}
+	if op != "" {
+		term.literal, err = parseLiteral(strings.TrimSpace(rhs))
+		if err != nil {
+			return filterTerm{}, err
+		}
+	}
+	return term, nil
+}
+
+func parseLiteral(s string) (interface{}, error) {
+	switch {
+	case isQuoted(s):
+		return s[1 : len(s)-1], nil
+	case s == "true":
+		return true, nil
+	case s == "false":
+		return false, nil
+	case s == "null":
+		return nil, nil
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter value %q", s)
+	}
+	return n, nil
+}
+
+// splitTopLevel splits s on sep outside of quotes and brackets
+func splitTopLevel(s, sep string) []string {
+	var parts []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[' || c == '(':
+			depth++
+		case c == ']' || c == ')':
+			depth--
+		case depth == 0 && strings.HasPrefix(s[i:], sep):
+			parts = append(parts, s[start:i])
+			start = i + len(sep)
+			i += len(sep) - 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func (f *pathFilter) matches(node interface{}) bool {
+	for _, terms := range f.any {
+		all := true
+		for _, term := range terms {
+			if !term.matches(node) {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true
+		}
+	}
+	return false
+}
+
+func (t filterTerm) matches(node interface{}) bool {
+	values := t.path.eval(node)
+	if t.op == "" {
+		return len(values) > 0 && truthy(values[0])
+	}
+	for _, v := range values {
+		if compareLiteral(v, t.op, t.literal) {
+			return true
+		}
+	}
+	// A missing member only satisfies "!="
+	return len(values) == 0 && t.op == "!="
+}
+
+func truthy(v interface{}) bool {
+	switch vv := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return vv
+	case string:
+		return vv != ""
+	}
+	return true
+}
+
+func compareLiteral(v interface{}, op string, literal interface{}) bool {
+	var cmp int
+	switch lit := literal.(type) {
+	case float64:
+		n, ok := v.(float64)
+		if !ok {
+			return op == "!="
+		}
+		switch {
+		case n < lit:
+			cmp = -1
+		case n > lit:
+			cmp = 1
+		}
+	case string:
+		s, ok := v.(string)
+		if !ok {
+			return op == "!="
+		}
+		cmp = strings.Compare(s, lit)
+	default:
+		// bool and null support only equality
+		equal := v == literal
+		switch op {
+		case "==":
+			return equal
+		case "!=":
+			return !equal
+		}
+		return false
+	}
+
+	switch op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
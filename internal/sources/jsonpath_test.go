@@ -0,0 +1,128 @@
+package sources
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// goDownloads is a trimmed go.dev/dl/?mode=json&include=all response
+const goDownloads = `[
+  {"version": "go1.23rc1", "stable": false, "files": [
+    {"filename": "go1.23rc1.linux-amd64.tar.gz", "os": "linux", "arch": "amd64", "sha256": "0000", "kind": "archive"}
+  ]},
+  {"version": "go1.22.1", "stable": true, "files": [
+    {"filename": "go1.22.1.src.tar.gz", "os": "", "arch": "", "sha256": "1111", "kind": "source"},
+    {"filename": "go1.22.1.linux-amd64.tar.gz", "os": "linux", "arch": "amd64", "sha256": "AAAA", "kind": "archive"},
+    {"filename": "go1.22.1.windows-amd64.msi", "os": "windows", "arch": "amd64", "sha256": "2222", "kind": "installer"},
+    {"filename": "go1.22.1.windows-amd64.zip", "os": "windows", "arch": "amd64", "sha256": "BBBB", "kind": "archive"}
+  ]},
+  {"version": "go1.21.8", "stable": true, "files": [
+    {"filename": "go1.21.8.linux-amd64.tar.gz", "os": "linux", "arch": "amd64", "sha256": "CCCC", "kind": "archive"}
+  ]}
+]`
+
+func TestJSONPath(t *testing.T) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(goDownloads), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"$[0].version", "go1.23rc1"},
+		{"$[-1].version", "go1.21.8"},
+		{"$[*].version", "go1.23rc1,go1.22.1,go1.21.8"},
+		{"$[?(@.stable==true)].version", "go1.22.1,go1.21.8"},
+		{"$[?(@.stable!=true)].version", "go1.23rc1"},
+		{"$[1].files[?(@.os=='windows' && @.kind=='archive')].filename", "go1.22.1.windows-amd64.zip"},
+		{"$[1].files[?(@.kind=='source' || @.kind=='installer')].sha256", "1111,2222"},
+		{"$[1]['files'][1][\"sha256\"]", "AAAA"},
+		{"$..filename", "go1.23rc1.linux-amd64.tar.gz,go1.22.1.src.tar.gz,go1.22.1.linux-amd64.tar.gz,go1.22.1.windows-amd64.msi,go1.22.1.windows-amd64.zip,go1.21.8.linux-amd64.tar.gz"},
+		{"$[?(@.files[?(@.os=='windows')])].version", "go1.22.1"},
+		{"$[?(@.missing)].version", ""},
+	}
+	for _, tt := range tests {
+		path, err := compileJSONPath(tt.path)
+		if err != nil {
+			t.Errorf("compileJSONPath(%q) failed: %v", tt.path, err)
+			continue
+		}
+		var got []string
+		for _, v := range path.eval(doc) {
+			got = append(got, fmt.Sprint(v))
+		}
+		if strings.Join(got, ",") != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.path, tt.expected, strings.Join(got, ","))
+		}
+	}
+
+	for _, bad := range []string{"$[", "$.", "$[?(stable==true)]", "$[abc]", "$[?(@.a==nope)]"} {
+		if _, err := compileJSONPath(bad); err == nil {
+			t.Errorf("compileJSONPath(%q) should fail", bad)
+		}
+	}
+}
+
+func TestReleaseFields(t *testing.T) {
+	defer SetPlatform(CurrentPlatform())
+	SetPlatform(Platform{OS: "windows", Arch: "amd64"})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(goDownloads))
+	}))
+	defer server.Close()
+
+	src := &Source{
+		Name:          "go-jsonpath",
+		ReleasesURL:   server.URL + "/dl/?mode=json&include=all",
+		ReleasesPath:  "$[?(@.stable==true)]",
+		VersionField:  "version",
+		VersionPrefix: "go",
+		ReleaseFields: &ReleaseFields{
+			URL:      "files[?(@.os=='{os}' && @.arch=='{arch}' && @.kind=='archive')].filename",
+			Checksum: "files[?(@.os=='{os}' && @.arch=='{arch}' && @.kind=='archive')].sha256",
+		},
+	}
+
+	versions, err := src.FetchVersions()
+	if err != nil {
+		t.Fatalf("FetchVersions failed: %v", err)
+	}
+	if strings.Join(versions, ",") != "1.22.1,1.21.8" {
+		t.Errorf("Expected only stable versions, got %v", versions)
+	}
+
+	release, err := src.FindRelease("1.22.1", "")
+	if err != nil || release == nil {
+		t.Fatalf("FindRelease failed: %v, %v", release, err)
+	}
+	if release.URL != server.URL+"/dl/go1.22.1.windows-amd64.zip" {
+		t.Errorf("Expected relative file name resolved against releasesUrl, got %s", release.URL)
+	}
+	if release.Checksum != "bbbb" {
+		t.Errorf("Expected Windows archive checksum, got %q", release.Checksum)
+	}
+
+	// No archive for this platform: nothing to report, templates apply
+	release, _ = src.FindRelease("1.21.8", "")
+	if release == nil || release.URL != "" || release.Checksum != "" {
+		t.Errorf("Expected empty per-version data for 1.21.8 on windows, got %+v", release)
+	}
+}
+
+func TestReleaseFieldsLTS(t *testing.T) {
+	src := &Source{VersionField: "version", ReleaseFields: &ReleaseFields{LTS: "lts"}}
+	releases, err := src.parseReleases([]byte(`[{"version":"v22.1.0","lts":false},{"version":"v20.12.0","lts":"Iron"}]`))
+	if err != nil {
+		t.Fatalf("parseReleases failed: %v", err)
+	}
+	if len(releases) != 2 || releases[0].LTS != "" || releases[1].LTS != "Iron" {
+		t.Errorf("Expected LTS codename only on 20.12.0, got %+v", releases)
+	}
+}
@@ -0,0 +1,183 @@
+package sources
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// KindMaven marks a source published to a Maven repository. Versions come
+// from the artifact's maven-metadata.xml and checksums from the .sha256 or
+// .sha1 file next to each download.
+const KindMaven = "maven"
+
+// DefaultMavenRepository is Maven Central
+const DefaultMavenRepository = "https://repo1.maven.org/maven2"
+
+var (
+	mavenMu         sync.Mutex
+	mavenRepository string // Repository from config; a definition's own repository wins
+)
+
+// SetMavenRepository sets the repository (e.g., a Nexus or Artifactory
+// mirror of Central) used by Maven sources that do not name one
+func SetMavenRepository(repoURL string) {
+	mavenMu.Lock()
+	defer mavenMu.Unlock()
+	mavenRepository = repoURL
+}
+
+// repository returns the base URL of the source's Maven repository
+func (s *Source) repository() string {
+	repo := s.Repository
+	if repo == "" {
+		mavenMu.Lock()
+		repo = mavenRepository
+		mavenMu.Unlock()
+	}
+	if repo == "" {
+		repo = DefaultMavenRepository
+	}
+	return strings.TrimSuffix(repo, "/")
+}
+
+// mavenCoordinates splits "groupId/artifactId" (or "groupId:artifactId")
+func (s *Source) mavenCoordinates() (groupID, artifactID string, err error) {
+	groupID, artifactID, ok := strings.Cut(s.Artifact, ":")
+	if !ok {
+		idx := strings.LastIndex(s.Artifact, "/")
+		if idx < 0 {
+			return "", "", fmt.Errorf("%s: artifact %q must be groupId/artifactId", s.Name, s.Artifact)
+		}
+		groupID, artifactID = s.Artifact[:idx], s.Artifact[idx+1:]
+	}
+	if groupID == "" || artifactID == "" {
+		return "", "", fmt.Errorf("%s: artifact %q must be groupId/artifactId", s.Name, s.Artifact)
+	}
+	return groupID, artifactID, nil
+}
+
+// artifactURL returns the directory holding every version of the artifact,
+// e.g. https://repo1.maven.org/maven2/com/lihaoyi/mill-dist
+func (s *Source) artifactURL() string {
+	groupID, artifactID, err := s.mavenCoordinates()
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s/%s", s.repository(), strings.ReplaceAll(groupID, ".", "/"), artifactID)
+}
+
+// mavenMetadataURL returns the URL of the artifact's maven-metadata.xml
+func (s *Source) mavenMetadataURL() string {
+	base := s.artifactURL()
+	if base == "" {
+		return ""
+	}
+	return base + "/maven-metadata.xml"
+}
+
+// mavenDownloadURL returns the template of the artifact file for a version:
+// {artifactId}-{version}[-{classifier}].{packaging}
+func (s *Source) mavenDownloadURL() string {
+	_, artifactID, err := s.mavenCoordinates()
+	if err != nil {
+		return ""
+	}
+	name := artifactID + "-{version}"
+	if s.Classifier != "" {
+		name += "-" + s.Classifier
+	}
+	return fmt.Sprintf("%s/{version}/%s.%s", s.artifactURL(), name, s.packaging())
+}
+
+// packaging returns the artifact's file extension, defaulting to the
+// archive format the source downloads
+func (s *Source) packaging() string {
+	if s.Packaging != "" {
+		return s.Packaging
+	}
+	switch s.DownloadType {
+	case "", "auto":
+		return "zip"
+	case "file":
+		return "jar"
+	}
+	return s.DownloadType
+}
+
+// fetchMavenVersions lists versions from maven-metadata.xml
+func (s *Source) fetchMavenVersions() ([]string, error) {
+	metadataURL := s.listingURL()
+	if metadataURL == "" {
+		_, _, err := s.mavenCoordinates()
+		return nil, err
+	}
+
+	body, err := fetchMetadata(metadataURL, "application/xml")
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata struct {
+		Versioning struct {
+			Versions []string `xml:"versions>version"`
+		} `xml:"versioning"`
+	}
+	if err := xml.Unmarshal(body, &metadata); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", metadataURL, err)
+	}
+
+	// maven-metadata.xml lists versions oldest first
+	versions := make([]string, 0, len(metadata.Versioning.Versions))
+	for i := len(metadata.Versioning.Versions) - 1; i >= 0; i-- {
+		if v := s.extractVersion(strings.TrimSpace(metadata.Versioning.Versions[i])); v != "" {
+			versions = append(versions, v)
+		}
+	}
+	return versions, nil
+}
+
+// SidecarChecksum returns the checksum published next to a Maven download,
+// preferring .sha256 over .sha1. SHA-1 checksums are returned as "sha1:<hex>".
+// The sidecar files are fetched from the repository on every call. Returns ""
+// for other kinds of sources or when no checksum is published.
+func (s *Source) SidecarChecksum(version, dist string) string {
+	if s.Kind != KindMaven || s.ChecksumURL != "" {
+		return ""
+	}
+	downloadURL := s.GetDownloadURLWithDist(version, dist)
+	if downloadURL == "" {
+		return ""
+	}
+
+	for _, sidecar := range []struct {
+		ext    string
+		length int
+		prefix string
+	}{
+		{".sha256", 64, ""},
+		{".sha1", 40, "sha1:"},
+	} {
+		body, err := fetchMetadata(downloadURL+sidecar.ext, "")
+		if err != nil {
+			continue
+		}
+		// Either the bare digest or "<digest>  <file name>"
+		fields := strings.Fields(string(body))
+		if len(fields) > 0 && len(fields[0]) == sidecar.length && isHex(fields[0]) {
+			return sidecar.prefix + strings.ToLower(fields[0])
+		}
+	}
+	return ""
+}
+
+// isHex reports whether s contains only hexadecimal digits
+func isHex(s string) bool {
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
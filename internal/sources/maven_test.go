@@ -0,0 +1,131 @@
+package sources
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const millMetadata = `<?xml version="1.0" encoding="UTF-8"?>
+<metadata>
+  <groupId>com.lihaoyi</groupId>
+  <artifactId>mill-dist</artifactId>
+  <versioning>
+    <latest>0.12.5</latest>
+    <release>0.12.5</release>
+    <versions>
+      <version>0.11.13</version>
+      <version>0.12.0-RC1</version>
+      <version>0.12.4</version>
+      <version>0.12.5</version>
+    </versions>
+  </versioning>
+</metadata>`
+
+func newMavenServer(t *testing.T, files map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func mavenTestSource(repoURL string) *Source {
+	return &Source{
+		Kind:         KindMaven,
+		Name:         "mill-maven",
+		DisplayName:  "Mill",
+		Repository:   repoURL,
+		Artifact:     "com.lihaoyi/mill-dist",
+		Classifier:   "mill",
+		Packaging:    "bat",
+		DownloadType: "file",
+		VersionRegex: `^\d+\.\d+\.\d+$`,
+	}
+}
+
+func TestMavenVersions(t *testing.T) {
+	withMetadataCache(t)
+	server := newMavenServer(t, map[string]string{
+		"/maven2/com/lihaoyi/mill-dist/maven-metadata.xml": millMetadata,
+	})
+	src := mavenTestSource(server.URL + "/maven2/")
+
+	versions, err := src.FetchVersions()
+	if err != nil {
+		t.Fatalf("FetchVersions failed: %v", err)
+	}
+	expected := []string{"0.12.5", "0.12.4", "0.11.13"}
+	if strings.Join(versions, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v (newest first, no prereleases), got %v", expected, versions)
+	}
+
+	resolved, err := src.ResolveVersion("0.12")
+	if err != nil || resolved != "0.12.5" {
+		t.Errorf("ResolveVersion(0.12): expected 0.12.5, got %q, %v", resolved, err)
+	}
+
+	url := src.GetDownloadURL("0.12.5")
+	if url != server.URL+"/maven2/com/lihaoyi/mill-dist/0.12.5/mill-dist-0.12.5-mill.bat" {
+		t.Errorf("Unexpected download URL: %s", url)
+	}
+}
+
+func TestMavenRepositoryFromConfig(t *testing.T) {
+	src := &Source{Kind: KindMaven, Name: "maven-mirror", Artifact: "org.apache.maven:apache-maven", Classifier: "bin"}
+	if url := src.GetDownloadURL("3.9.6"); url != DefaultMavenRepository+"/org/apache/maven/apache-maven/3.9.6/apache-maven-3.9.6-bin.zip" {
+		t.Errorf("Expected Maven Central download, got %s", url)
+	}
+
+	SetMavenRepository("https://nexus.example.com/repository/central/")
+	defer SetMavenRepository("")
+	if url := src.mavenMetadataURL(); url != "https://nexus.example.com/repository/central/org/apache/maven/apache-maven/maven-metadata.xml" {
+		t.Errorf("Expected configured repository, got %s", url)
+	}
+
+	src.Repository = "https://repo.example.org"
+	if url := src.mavenMetadataURL(); !strings.HasPrefix(url, "https://repo.example.org/") {
+		t.Errorf("Expected the definition's repository to win, got %s", url)
+	}
+}
+
+func TestMavenSidecarChecksum(t *testing.T) {
+	withMetadataCache(t)
+	sha256Hex := strings.Repeat("ab", 32)
+	sha1Hex := strings.Repeat("CD", 20)
+	server := newMavenServer(t, map[string]string{
+		"/com/lihaoyi/mill-dist/0.12.5/mill-dist-0.12.5-mill.bat.sha256": sha256Hex + "  mill-dist-0.12.5-mill.bat\n",
+		"/com/lihaoyi/mill-dist/0.12.4/mill-dist-0.12.4-mill.bat.sha1":   sha1Hex,
+	})
+	src := mavenTestSource(server.URL)
+
+	if got := src.SidecarChecksum("0.12.5", ""); got != sha256Hex {
+		t.Errorf("Expected .sha256 checksum, got %q", got)
+	}
+	if got := src.SidecarChecksum("0.12.4", ""); got != "sha1:"+strings.ToLower(sha1Hex) {
+		t.Errorf("Expected .sha1 fallback, got %q", got)
+	}
+	if got := src.SidecarChecksum("0.11.13", ""); got != "" {
+		t.Errorf("Expected no checksum when none is published, got %q", got)
+	}
+}
+
+func TestMavenDefinitions(t *testing.T) {
+	for _, name := range []string{"maven", "mill"} {
+		src, ok := Get(name)
+		if !ok {
+			t.Errorf("Source %s not found", name)
+			continue
+		}
+		if src.Kind != KindMaven || src.Artifact == "" {
+			t.Errorf("Source %s should be read from a Maven repository, got kind %q", name, src.Kind)
+		}
+	}
+}
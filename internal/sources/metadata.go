@@ -0,0 +1,331 @@
+package sources
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotCached is returned in offline mode when data needed to answer a
+// request was never fetched while online
+var ErrNotCached = errors.New("not available offline")
+
+// DefaultCacheTTL is how long a cached release listing is used without
+// asking the server whether it changed
+const DefaultCacheTTL = time.Hour
+
+var (
+	metadataMu  sync.Mutex
+	metadataDir string // Where release listings are cached, "" to disable
+	cacheTTL    = DefaultCacheTTL
+	refresh     bool
+	offline     bool
+)
+
+// SetCacheDir sets the directory where fetched release listings are kept
+// for offline use
+func SetCacheDir(dir string) {
+	metadataMu.Lock()
+	defer metadataMu.Unlock()
+	metadataDir = dir
+}
+
+// SetCacheTTL sets how long cached release listings are used before they are
+// revalidated with the server. Zero revalidates on every use.
+func SetCacheTTL(ttl time.Duration) {
+	metadataMu.Lock()
+	defer metadataMu.Unlock()
+	cacheTTL = ttl
+}
+
+// SetRefresh makes every release listing be fetched again, ignoring the TTL
+// and the server's caching headers
+func SetRefresh(enabled bool) {
+	metadataMu.Lock()
+	defer metadataMu.Unlock()
+	refresh = enabled
+}
+
+// SetOffline enables or disables offline mode. Offline, all release metadata
+// comes from the cache and nothing is requested over the network.
+func SetOffline(enabled bool) {
+	metadataMu.Lock()
+	defer metadataMu.Unlock()
+	offline = enabled
+}
+
+// Offline reports whether offline mode is enabled
+func Offline() bool {
+	metadataMu.Lock()
+	defer metadataMu.Unlock()
+	return offline
+}
+
+// cachedResponse is a release listing as stored on disk
+type cachedResponse struct {
+	URL          string    `json:"url"`
+	Fetched      time.Time `json:"fetched"` // Last time the server confirmed this body
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	Next         string    `json:"next,omitempty"` // Next page from the Link header
+	Body         string    `json:"body"`
+}
+
+// metadataPath returns the cache file for a URL, or "" when caching is disabled
+func metadataPath(rawURL string) string {
+	metadataMu.Lock()
+	dir := metadataDir
+	metadataMu.Unlock()
+	if dir == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(rawURL))
+	return filepath.Join(dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+// fetchMetadata GETs a release listing through the on-disk cache and
+// returns its body
+func fetchMetadata(rawURL, accept string) ([]byte, error) {
+	header := http.Header{}
+	if accept != "" {
+		header.Set("Accept", accept)
+	}
+	resp, err := fetchResponse(rawURL, header)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(resp.Body), nil
+}
+
+// fetchResponse GETs a release listing through the on-disk cache. Listings
+// younger than the TTL are used as-is; older ones are revalidated with
+// If-None-Match/If-Modified-Since. Offline, when the network is unreachable,
+// or when the server is rate limiting or failing, the cached copy is returned.
+func fetchResponse(rawURL string, header http.Header) (*cachedResponse, error) {
+	cached, cacheErr := readMetadata(rawURL)
+
+	if Offline() {
+		if cacheErr != nil {
+			return nil, fmt.Errorf("%w: %s has not been fetched before", ErrNotCached, rawURL)
+		}
+		return cached, nil
+	}
+
+	metadataMu.Lock()
+	ttl, forceRefresh := cacheTTL, refresh
+	metadataMu.Unlock()
+
+	var validators *cachedResponse
+	if cacheErr == nil && !forceRefresh {
+		if time.Since(cached.Fetched) < ttl {
+			return cached, nil
+		}
+		validators = cached
+	}
+
+	resp, err := httpGet(rawURL, header, validators)
+	if err != nil {
+		var statusErr *httpStatusError
+		if cacheErr == nil && !errors.As(err, &statusErr) {
+			// Transport failure (no network, DNS, timeout): fall back to the last copy
+			return cached, nil
+		}
+		if cacheErr == nil && staleOK(statusErr.Status) {
+			reason := err
+			if gitHubTokenFor(rawURL) {
+				reason = githubError(err, header.Get("Authorization") != "")
+			}
+			fmt.Fprintf(os.Stderr, "Warning: %v; using the release list cached %s ago\n",
+				reason, FormatAge(time.Since(cached.Fetched)))
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	if resp.notModified {
+		cached.Fetched = time.Now()
+		writeMetadata(cached)
+		return cached, nil
+	}
+
+	fresh := &cachedResponse{
+		URL:          rawURL,
+		Fetched:      time.Now(),
+		ETag:         resp.etag,
+		LastModified: resp.lastModified,
+		Next:         resp.next,
+		Body:         string(resp.body),
+	}
+	writeMetadata(fresh)
+	return fresh, nil
+}
+
+// staleOK reports whether a cached listing may stand in for an HTTP error:
+// rate limits (403, 429) and server failures, but not answers such as 404
+func staleOK(status int) bool {
+	return status == http.StatusForbidden || status == http.StatusTooManyRequests || status >= 500
+}
+
+// FormatAge formats a duration coarsely: 45m, 3h, 2d
+func FormatAge(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+// CacheAge reports how long ago the source's release list was last fetched
+// or confirmed by the server. ok is false when it is not cached.
+func (s *Source) CacheAge() (age time.Duration, ok bool) {
+	listingURL := s.listingURL()
+	if listingURL == "" {
+		return 0, false
+	}
+	cached, err := readMetadata(listingURL)
+	if err != nil {
+		return 0, false
+	}
+	return time.Since(cached.Fetched), true
+}
+
+// httpStatusError is a non-200 answer from a server that was reachable
+type httpStatusError struct {
+	URL    string
+	Status int
+	Header http.Header
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("HTTP %d from %s", e.Status, e.URL)
+}
+
+type httpResponse struct {
+	body         []byte
+	etag         string
+	lastModified string
+	next         string
+	notModified  bool
+}
+
+// httpGet performs a GET, made conditional when validators from a cached
+// copy are given
+func httpGet(rawURL string, header http.Header, validators *cachedResponse) (*httpResponse, error) {
+	req, err := http.NewRequest("GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if validators != nil {
+		if validators.ETag != "" {
+			req.Header.Set("If-None-Match", validators.ETag)
+		}
+		if validators.LastModified != "" {
+			req.Header.Set("If-Modified-Since", validators.LastModified)
+		}
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotModified && validators != nil {
+		return &httpResponse{notModified: true}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &httpStatusError{URL: rawURL, Status: resp.StatusCode, Header: resp.Header}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &httpResponse{
+		body:         body,
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+		next:         nextLink(resp.Header.Get("Link")),
+	}, nil
+}
+
+// nextLink extracts the rel="next" URL from an RFC 8288 Link header, e.g.
+// <https://api.github.com/...?page=2>; rel="next", <...>; rel="last"
+func nextLink(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		target, params, ok := strings.Cut(strings.TrimSpace(part), ";")
+		if !ok {
+			continue
+		}
+		for _, param := range strings.Split(params, ";") {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if name == "rel" && strings.Trim(value, `"`) == "next" {
+				return strings.Trim(strings.TrimSpace(target), "<>")
+			}
+		}
+	}
+	return ""
+}
+
+func readMetadata(rawURL string) (*cachedResponse, error) {
+	path := metadataPath(rawURL)
+	if path == "" {
+		return nil, os.ErrNotExist
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cached cachedResponse
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, err
+	}
+	if cached.URL != rawURL {
+		return nil, os.ErrNotExist
+	}
+	return &cached, nil
+}
+
+// writeMetadata stores a response. Failures are ignored: the cache is
+// only an optimization (and a source for offline use).
+func writeMetadata(cached *cachedResponse) {
+	path := metadataPath(cached.URL)
+	if path == "" {
+		return
+	}
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+
+	// Write to a temp file and rename so concurrent readers never see half a file
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".metadata-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil || os.Rename(tmp.Name(), path) != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
@@ -0,0 +1,221 @@
+package sources
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// withMetadataCache points the listing cache at a temp dir for one test
+func withMetadataCache(t *testing.T) {
+	t.Helper()
+	SetCacheDir(t.TempDir())
+	t.Cleanup(func() {
+		SetCacheDir("")
+		SetCacheTTL(DefaultCacheTTL)
+		SetRefresh(false)
+		SetOffline(false)
+	})
+}
+
+func TestOfflineUsesCachedReleaseList(t *testing.T) {
+	withMetadataCache(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"version":"v20.11.0"},{"version":"v20.10.0"},{"version":"v18.19.0"}]`))
+	}))
+	src := &Source{
+		Name:         "node-offline",
+		DisplayName:  "Node.js",
+		ReleasesURL:  server.URL + "/index.json",
+		VersionField: "version",
+	}
+
+	// Online run caches the listing
+	if _, err := src.FetchVersions(); err != nil {
+		t.Fatalf("FetchVersions failed: %v", err)
+	}
+	server.Close()
+
+	SetOffline(true)
+	resolved, err := src.ResolveVersion("20")
+	if err != nil {
+		t.Fatalf("ResolveVersion offline failed: %v", err)
+	}
+	if resolved != "20.11.0" {
+		t.Errorf("Expected 20.11.0 from cached listing, got %s", resolved)
+	}
+}
+
+func TestOfflineWithoutCacheFailsFast(t *testing.T) {
+	withMetadataCache(t)
+	SetOffline(true)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	src := &Source{Name: "never-fetched", ReleasesURL: server.URL + "/releases.json", VersionField: "version"}
+	for _, v := range []string{"3.9", "21", "3.9.6"} {
+		if _, err := src.ResolveVersion(v); !errors.Is(err, ErrNotCached) {
+			t.Errorf("ResolveVersion(%q): expected ErrNotCached, got %v", v, err)
+		}
+	}
+	if requests != 0 {
+		t.Errorf("Offline mode must not hit the network, got %d requests", requests)
+	}
+}
+
+func TestUnreachableServerFallsBackToCache(t *testing.T) {
+	withMetadataCache(t)
+	SetCacheTTL(0)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"result":[{"major_version":21}]}`))
+	}))
+	url := server.URL + "/disco/v3.0/major_versions"
+
+	var first struct {
+		Result []struct {
+			MajorVersion int `json:"major_version"`
+		}
+	}
+	if err := getJSON(url, &first); err != nil {
+		t.Fatalf("getJSON failed: %v", err)
+	}
+	server.Close()
+
+	// Connection refused is a transport error, so the cached copy answers
+	var second struct {
+		Result []struct {
+			MajorVersion int `json:"major_version"`
+		}
+	}
+	if err := getJSON(url, &second); err != nil {
+		t.Fatalf("Expected cached fallback, got %v", err)
+	}
+	if len(second.Result) != 1 || second.Result[0].MajorVersion != 21 {
+		t.Errorf("Unexpected cached result: %+v", second)
+	}
+}
+
+func TestHTTPErrorIsNotMaskedByCache(t *testing.T) {
+	withMetadataCache(t)
+	SetCacheTTL(0)
+
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	var v []string
+	if err := getJSON(server.URL, &v); err != nil {
+		t.Fatalf("getJSON failed: %v", err)
+	}
+
+	// A reachable server saying 404 is authoritative
+	status = http.StatusNotFound
+	if err := getJSON(server.URL, &v); err == nil {
+		t.Error("Expected HTTP 404 error")
+	}
+}
+
+func TestRateLimitFallsBackToCache(t *testing.T) {
+	withMetadataCache(t)
+	SetCacheTTL(0)
+
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status == http.StatusForbidden {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		}
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			_, _ = w.Write([]byte(`["1.0"]`))
+		}
+	}))
+	defer server.Close()
+
+	var v []string
+	if err := getJSON(server.URL, &v); err != nil {
+		t.Fatalf("getJSON failed: %v", err)
+	}
+
+	// Rate limits and server failures are served from the expired copy
+	for _, status = range []int{http.StatusForbidden, http.StatusTooManyRequests, http.StatusBadGateway} {
+		v = nil
+		if err := getJSON(server.URL, &v); err != nil {
+			t.Errorf("HTTP %d: expected cached fallback, got %v", status, err)
+		}
+		if len(v) != 1 || v[0] != "1.0" {
+			t.Errorf("HTTP %d: unexpected cached result %v", status, v)
+		}
+	}
+}
+
+func TestCacheTTLAndRevalidation(t *testing.T) {
+	withMetadataCache(t)
+
+	var full, conditional int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			conditional++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full++
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`[{"version":"3.9.6"},{"version":"3.9.5"}]`))
+	}))
+	defer server.Close()
+
+	src := &Source{Name: "maven-ttl", ReleasesURL: server.URL + "/releases", VersionField: "version"}
+	fetch := func() {
+		t.Helper()
+		versions, err := src.FetchVersions()
+		if err != nil || len(versions) != 2 {
+			t.Fatalf("FetchVersions: %v, %v", versions, err)
+		}
+	}
+
+	// Within the TTL the server is not asked at all
+	fetch()
+	fetch()
+	if full != 1 || conditional != 0 {
+		t.Errorf("Expected 1 full request within TTL, got %d full, %d conditional", full, conditional)
+	}
+	if age, ok := src.CacheAge(); !ok || age > time.Minute {
+		t.Errorf("Expected fresh cache age, got %v, %v", age, ok)
+	}
+
+	// Expired: revalidated with the ETag, 304 keeps the cached body
+	SetCacheTTL(0)
+	fetch()
+	if full != 1 || conditional != 1 {
+		t.Errorf("Expected a conditional request after expiry, got %d full, %d conditional", full, conditional)
+	}
+
+	// --refresh refetches unconditionally
+	SetRefresh(true)
+	fetch()
+	if full != 2 {
+		t.Errorf("Expected refresh to bypass the cache, got %d full requests", full)
+	}
+}
+
+func TestCacheAgeWithoutCache(t *testing.T) {
+	withMetadataCache(t)
+
+	src := &Source{Name: "uncached", ReleasesURL: "https://example.com/releases"}
+	if _, ok := src.CacheAge(); ok {
+		t.Error("Expected no cache age for a never-fetched source")
+	}
+}
@@ -0,0 +1,112 @@
+package sources
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Platform identifies the operating system and CPU architecture to install for
+type Platform struct {
+	OS   string
+	Arch string
+}
+
+// String returns the platform in "os/arch" form
+func (p Platform) String() string {
+	return p.OS + "/" + p.Arch
+}
+
+// ParsePlatform parses an "os/arch" string (e.g., "linux/arm64")
+func ParsePlatform(s string) (Platform, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return Platform{}, fmt.Errorf("invalid platform %q (expected os/arch, e.g. linux/amd64)", s)
+	}
+	return Platform{OS: strings.ToLower(parts[0]), Arch: strings.ToLower(parts[1])}, nil
+}
+
+// HostPlatform returns the platform verman is running on
+func HostPlatform() Platform {
+	return Platform{OS: runtime.GOOS, Arch: runtime.GOARCH}
+}
+
+// targetPlatform is the platform used to expand {os}, {arch} and {ext} placeholders
+var targetPlatform = HostPlatform()
+
+// CurrentPlatform returns the platform downloads are resolved for
+func CurrentPlatform() Platform {
+	return targetPlatform
+}
+
+// SetPlatform overrides the target platform (e.g., from "verman install --platform")
+func SetPlatform(p Platform) {
+	targetPlatform = p
+}
+
+// PlatformMap holds per-source naming tables for platform placeholders.
+// Keys are Go's GOOS/GOARCH values; unmapped values are used as-is.
+type PlatformMap struct {
+	OS   map[string]string `json:"os,omitempty"`   // e.g., {"windows": "win", "darwin": "mac"}
+	Arch map[string]string `json:"arch,omitempty"` // e.g., {"amd64": "x64", "arm64": "aarch64"}
+	Ext  map[string]string `json:"ext,omitempty"`  // Archive extension keyed by GOOS, e.g., {"windows": "zip"}
+}
+
+// defaultExt is used for {ext} when no mapping is defined for the target OS
+func defaultExt(goos string) string {
+	if goos == "windows" {
+		return "zip"
+	}
+	return "tar.gz"
+}
+
+// merge returns a copy of m with entries from override taking precedence
+func (m *PlatformMap) merge(override *PlatformMap) *PlatformMap {
+	if override == nil {
+		return m
+	}
+	if m == nil {
+		return override
+	}
+	return &PlatformMap{
+		OS:   mergeStrings(m.OS, override.OS),
+		Arch: mergeStrings(m.Arch, override.Arch),
+		Ext:  mergeStrings(m.Ext, override.Ext),
+	}
+}
+
+func mergeStrings(base, override map[string]string) map[string]string {
+	result := make(map[string]string, len(base)+len(override))
+	for k, v := range base {
+		result[k] = v
+	}
+	for k, v := range override {
+		result[k] = v
+	}
+	return result
+}
+
+// replacePlatform expands {os}, {arch} and {ext} in s for the given platform
+func (m *PlatformMap) replacePlatform(s string, p Platform) string {
+	if !strings.Contains(s, "{") {
+		return s
+	}
+
+	osName, arch, ext := p.OS, p.Arch, defaultExt(p.OS)
+	if m != nil {
+		if v, ok := m.OS[p.OS]; ok {
+			osName = v
+		}
+		if v, ok := m.Arch[p.Arch]; ok {
+			arch = v
+		}
+		if v, ok := m.Ext[p.OS]; ok {
+			ext = v
+		}
+	}
+
+	s = strings.ReplaceAll(s, "{os}", osName)
+	s = strings.ReplaceAll(s, "{arch}", arch)
+	s = strings.ReplaceAll(s, "{ext}", ext)
+	return s
+}
@@ -0,0 +1,227 @@
+package sources
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/azdren/verman/internal/semver"
+)
+
+// Release is a concrete downloadable build reported by a vendor API
+type Release struct {
+	Version  string // Full version, e.g., "21.0.2" (Temurin) or "21.0.2.13.1" (Corretto)
+	Build    string // Vendor build identifier, e.g., "21.0.2+13"
+	URL      string // Direct download URL for the target platform
+	Checksum string // SHA256 of the download, empty if the vendor does not publish one
+	LTS      string // LTS codename or "true" for long-term support releases, empty otherwise
+	Date     string // Release date (YYYY-MM-DD) when the listing publishes one
+
+	// infoURL points at per-package metadata holding URL and Checksum when
+	// the listing endpoint does not include them (Foojay)
+	infoURL string
+}
+
+// releaseFetcher queries a vendor API for all builds of a Java feature release
+type releaseFetcher func(d *Distribution, baseURL, major string, p Platform) ([]Release, error)
+
+// releaseAPIs maps a distribution's "api" field to its fetcher and default base URL
+var releaseAPIs = map[string]struct {
+	fetch   releaseFetcher
+	baseURL string
+}{
+	"foojay": {fetchFoojayReleases, "https://api.foojay.io"},
+}
+
+// distAPI returns the release API a distribution uses. Distributions of a
+// "foojay" source default to the Disco API.
+func (s *Source) distAPI(d *Distribution) string {
+	if d.API == "" && s.Kind == KindFoojay {
+		return "foojay"
+	}
+	return d.API
+}
+
+// releaseBaseURL returns the base URL of a distribution's release API: its
+// own apiUrl, else the API's default. The source's apiUrl names the Disco
+// API, so only foojay distributions fall back to it; a vendor API must not
+// be queried (or sent a token) on another vendor's host.
+func (s *Source) releaseBaseURL(d *Distribution) string {
+	name := s.distAPI(d)
+	switch {
+	case d.APIURL != "":
+		return d.APIURL
+	case name == "foojay" && s.APIURL != "":
+		return s.APIURL
+	}
+	return releaseAPIs[name].baseURL
+}
+
+var (
+	releaseCacheMu sync.Mutex
+	releaseCache   = make(map[string][]Release)
+)
+
+// HasReleaseAPI reports whether exact builds can be resolved for a distribution
+func (s *Source) HasReleaseAPI(dist string) bool {
+	d, ok := s.Distributions[s.resolveDist(dist)]
+	if !ok {
+		return false
+	}
+	_, ok = releaseAPIs[s.distAPI(d)]
+	return ok
+}
+
+// FetchReleases returns the concrete builds of a major version for a distribution,
+// newest first. Results are cached for the lifetime of the process.
+func (s *Source) FetchReleases(dist, major string) ([]Release, error) {
+	dist = s.resolveDist(dist)
+	d, ok := s.Distributions[dist]
+	if !ok {
+		return nil, fmt.Errorf("unknown distribution: %s", dist)
+	}
+	api, ok := releaseAPIs[s.distAPI(d)]
+	if !ok {
+		return nil, fmt.Errorf("distribution %s has no release API", dist)
+	}
+
+	baseURL := s.releaseBaseURL(d)
+
+	key := strings.Join([]string{s.Name, dist, major, targetPlatform.String()}, "|")
+	releaseCacheMu.Lock()
+	cached, ok := releaseCache[key]
+	releaseCacheMu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	releases, err := api.fetch(d, strings.TrimSuffix(baseURL, "/"), major, targetPlatform)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s releases: %w", d.DisplayName, err)
+	}
+	sortReleases(releases)
+
+	releaseCacheMu.Lock()
+	releaseCache[key] = releases
+	releaseCacheMu.Unlock()
+	return releases, nil
+}
+
+// FindRelease returns the build for an exact version, or nil if the distribution
+// has no release API and the release listing carries no per-version data
+// (releaseFields). A bare major version resolves to nil (use the "latest"
+// URL template) when the distribution has one, otherwise to its newest build.
+func (s *Source) FindRelease(version, dist string) (*Release, error) {
+	if !s.HasReleaseAPI(dist) {
+		return s.findListedRelease(version), nil
+	}
+	bareMajor := !strings.Contains(version, ".")
+	if bareMajor && s.hasLatestURL(dist) {
+		return nil, nil
+	}
+
+	releases, err := s.FetchReleases(dist, strings.Split(version, ".")[0])
+	if err != nil {
+		return nil, err
+	}
+	for i := range releases {
+		if releases[i].Version == version || (bareMajor && i == 0) {
+			return s.completeRelease(&releases[i])
+		}
+	}
+	return nil, fmt.Errorf("%s %s is not available from %s", s.DisplayName, version, s.GetDistributionDisplayName(s.resolveDist(dist)))
+}
+
+// findListedRelease returns the entry for an exact version from a release
+// listing with releaseFields, or nil when the listing has no per-version
+// download data (the URL templates apply)
+func (s *Source) findListedRelease(version string) *Release {
+	if s.ReleaseFields == nil || (s.ReleaseFields.URL == "" && s.ReleaseFields.Checksum == "") ||
+		s.ReleasesURL == "" || s.Kind != "" {
+		return nil
+	}
+	body, err := fetchMetadata(s.ReleasesURL, "")
+	if err != nil {
+		return nil
+	}
+	releases, err := s.parseReleases(body)
+	if err != nil {
+		return nil
+	}
+	for i := range releases {
+		if releases[i].Version == version {
+			return &releases[i]
+		}
+	}
+	return nil
+}
+
+// hasLatestURL reports whether a distribution has a download URL template
+// that serves the newest build of a major version
+func (s *Source) hasLatestURL(dist string) bool {
+	if d, ok := s.Distributions[s.resolveDist(dist)]; ok {
+		return d.DownloadURL != ""
+	}
+	return s.DownloadURL != ""
+}
+
+// completeRelease fills in the download URL and checksum of a release whose
+// listing only carried a metadata link
+func (s *Source) completeRelease(r *Release) (*Release, error) {
+	if r.URL != "" || r.infoURL == "" {
+		return r, nil
+	}
+
+	var info struct {
+		Result []struct {
+			DirectDownloadURI string `json:"direct_download_uri"`
+			Checksum          string `json:"checksum"`
+			ChecksumType      string `json:"checksum_type"`
+		} `json:"result"`
+	}
+	if err := getJSON(r.infoURL, &info); err != nil {
+		return nil, fmt.Errorf("fetching package info: %w", err)
+	}
+	if len(info.Result) == 0 || info.Result[0].DirectDownloadURI == "" {
+		return nil, fmt.Errorf("no download available for %s %s", s.DisplayName, r.Version)
+	}
+
+	releaseCacheMu.Lock()
+	defer releaseCacheMu.Unlock()
+	r.URL = info.Result[0].DirectDownloadURI
+	if strings.EqualFold(info.Result[0].ChecksumType, "sha256") {
+		r.Checksum = info.Result[0].Checksum
+	}
+	return r, nil
+}
+
+// sortReleases orders releases newest first
+func sortReleases(releases []Release) {
+	sort.SliceStable(releases, func(i, j int) bool {
+		return semver.Compare(releases[i].Version, releases[j].Version) > 0
+	})
+}
+
+// getJSON fetches a URL and decodes the JSON response into v
+func getJSON(rawURL string, v interface{}) error {
+	body, err := fetchMetadata(rawURL, "application/json")
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+// jdkArch maps GOARCH to the x64/aarch64 naming most JDK vendors use
+func jdkArch(arch string) string {
+	switch arch {
+	case "amd64":
+		return "x64"
+	case "arm64":
+		return "aarch64"
+	case "386":
+		return "x86"
+	}
+	return arch
+}
@@ -0,0 +1,22 @@
+package sources
+
+import "testing"
+
+func TestReleaseBaseURL(t *testing.T) {
+	tests := []struct {
+		src  *Source
+		dist *Distribution
+		want string
+	}{
+		{&Source{Kind: KindFoojay, APIURL: "https://disco.example"}, &Distribution{Name: "liberica"}, "https://disco.example"},
+		{&Source{Kind: KindFoojay, APIURL: "https://disco.example"}, &Distribution{Name: "zulu", APIURL: "https://disco.mirror"}, "https://disco.mirror"},
+		{&Source{Kind: KindFoojay}, &Distribution{Name: "liberica"}, "https://api.foojay.io"},
+		// A source-level apiUrl is not used for another API
+		{&Source{APIURL: "https://disco.example"}, &Distribution{Name: "temurin", API: "vendor"}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.src.releaseBaseURL(tt.dist); got != tt.want {
+			t.Errorf("releaseBaseURL(%s) = %q, want %q", tt.dist.Name, got, tt.want)
+		}
+	}
+}
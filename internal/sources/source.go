@@ -3,15 +3,20 @@ package sources
 import (
 	"embed"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/azdren/verman/internal/semver"
 )
 
 //go:embed definitions/*.json
@@ -19,38 +24,82 @@ var embeddedSources embed.FS
 
 // Distribution represents a vendor-specific distribution
 type Distribution struct {
-	Name        string `json:"name"`
-	DisplayName string `json:"displayName"`
-	DownloadURL string `json:"downloadUrl"`
-	ChecksumURL string `json:"checksumUrl,omitempty"` // URL for SHA256 checksum
+	Name        string       `json:"name"`
+	DisplayName string       `json:"displayName"`
+	DownloadURL string       `json:"downloadUrl"`
+	ChecksumURL string       `json:"checksumUrl,omitempty"` // URL for SHA256 checksum
+	Platforms   *PlatformMap `json:"platforms,omitempty"`   // Overrides the source's platform naming
+	API         string       `json:"api,omitempty"`         // Metadata API for exact builds: "foojay" (the default in foojay sources)
+	APIName     string       `json:"apiName,omitempty"`     // Distribution identifier in the vendor API (defaults to Name)
+	APIURL      string       `json:"apiUrl,omitempty"`      // Base URL override for the vendor API (e.g., a mirror)
 }
 
 // Source represents a language/tool source configuration
 type Source struct {
-	Name           string                   `json:"name"`
-	DisplayName    string                   `json:"displayName"`
-	ReleasesURL    string                   `json:"releasesUrl"`
-	ReleasesPath   string                   `json:"releasesPath,omitempty"`  // JSON path to versions array
-	VersionField   string                   `json:"versionField,omitempty"`  // Field name for version in releases
-	VersionPrefix  string                   `json:"versionPrefix,omitempty"` // Prefix to strip from versions (e.g., "maven-")
-	DownloadURL    string                   `json:"downloadUrl"`
-	ChecksumURL    string                   `json:"checksumUrl,omitempty"`    // URL for SHA256 checksum (supports {version} placeholder)
-	DownloadType   string                   `json:"downloadType,omitempty"`   // "zip" (default), "file" for single file downloads
-	ExtractPattern string                   `json:"extractPattern,omitempty"` // Folder name inside archive
-	VersionRegex   string                   `json:"versionRegex"`
-	VersionFiles   []string                 `json:"versionFiles"`
-	EnvVars        map[string]string        `json:"envVars"`
-	PathDirs       []string                 `json:"pathDirs"`
-	PostInstall    []string                 `json:"postInstall,omitempty"`         // Commands to run after install
-	Dependencies   []string                 `json:"dependencies,omitempty"`        // Other tools this depends on (e.g., ["java"])
-	Distributions  map[string]*Distribution `json:"distributions,omitempty"`       // Vendor distributions (for Java: tem, amzn, zulu)
-	DefaultDist    string                   `json:"defaultDistribution,omitempty"` // Default distribution key
-	StaticVersions []string                 `json:"staticVersions,omitempty"`      // Additional versions not in API (e.g., legacy versions)
+	Kind               string                   `json:"kind,omitempty"` // "" (URL templates), "foojay" (Disco API), "github" (releases API), "maven" (maven-metadata.xml) or "index" (HTML directory listing)
+	Name               string                   `json:"name"`
+	DisplayName        string                   `json:"displayName"`
+	ReleasesURL        string                   `json:"releasesUrl"`
+	ReleasesPath       string                   `json:"releasesPath,omitempty"`  // JSON path selecting the release entries, e.g. "$[?(@.stable==true)]"
+	VersionField       string                   `json:"versionField,omitempty"`  // JSON path to the version within each entry (default "version")
+	VersionPrefix      string                   `json:"versionPrefix,omitempty"` // Prefix to strip from versions (e.g., "maven-")
+	DownloadURL        string                   `json:"downloadUrl"`
+	ChecksumURL        string                   `json:"checksumUrl,omitempty"`    // URL for SHA256 checksum (supports {version} placeholder)
+	DownloadType       string                   `json:"downloadType,omitempty"`   // "zip" (default), "tar.gz", "tar.xz", "auto", or "file" for single file downloads
+	ExtractPattern     string                   `json:"extractPattern,omitempty"` // Folder name inside archive
+	VersionRegex       string                   `json:"versionRegex"`
+	VersionFiles       []string                 `json:"versionFiles"`
+	EnvVars            map[string]string        `json:"envVars"`
+	PathDirs           []string                 `json:"pathDirs"`
+	PostInstall        []string                 `json:"postInstall,omitempty"`         // Commands to run after install
+	Dependencies       []string                 `json:"dependencies,omitempty"`        // Other tools this depends on (e.g., ["java"])
+	Distributions      map[string]*Distribution `json:"distributions,omitempty"`       // Vendor distributions (for Java: tem, amzn, zulu)
+	DefaultDist        string                   `json:"defaultDistribution,omitempty"` // Default distribution key
+	StaticVersions     []string                 `json:"staticVersions,omitempty"`      // Additional versions not in API (e.g., legacy versions)
+	IncludePrereleases bool                     `json:"includePrereleases,omitempty"`  // Keep prereleases (RC, milestone, beta, ...) in version lists
+	Platforms          *PlatformMap             `json:"platforms,omitempty"`           // Naming tables for {os}, {arch} and {ext}
+	APIURL             string                   `json:"apiUrl,omitempty"`              // Base URL of the Foojay Disco API for distributions using it
+	Repository         string                   `json:"repository,omitempty"`          // Maven repository base URL (defaults to Maven Central)
+	Artifact           string                   `json:"artifact,omitempty"`            // Maven coordinates as "groupId/artifactId"
+	Classifier         string                   `json:"classifier,omitempty"`          // Maven classifier of the download (e.g., "bin")
+	Packaging          string                   `json:"packaging,omitempty"`           // Maven file extension of the download (e.g., "zip", "jar")
+	LinkRegex          string                   `json:"linkRegex,omitempty"`           // Regex picking versions from directory listing links (first group is the version)
+	ReleaseFields      *ReleaseFields           `json:"releaseFields,omitempty"`       // JSON paths to per-version data in each release entry
+}
+
+// ReleaseFields holds JSON paths, relative to one entry of the release list,
+// to data about that version. Paths may use {os}, {arch} and {ext}.
+type ReleaseFields struct {
+	URL      string `json:"url,omitempty"`      // Download URL; relative URLs resolve against releasesUrl
+	Checksum string `json:"checksum,omitempty"` // SHA256 of the download
+	LTS      string `json:"lts,omitempty"`      // LTS flag or codename
+	Date     string `json:"date,omitempty"`     // Release date
 }
 
 var loadedSources map[string]*Source
 var httpClient = &http.Client{Timeout: 30 * time.Second}
 
+var (
+	prereleasesMu      sync.Mutex
+	includePrereleases bool
+)
+
+// SetIncludePrereleases makes every source list (and resolve partial
+// versions to) prereleases such as release candidates and milestones
+func SetIncludePrereleases(enabled bool) {
+	prereleasesMu.Lock()
+	defer prereleasesMu.Unlock()
+	includePrereleases = enabled
+}
+
+// IncludesPrereleases reports whether prereleases are kept in this source's
+// version list, either by its definition or by SetIncludePrereleases
+func (s *Source) IncludesPrereleases() bool {
+	prereleasesMu.Lock()
+	defer prereleasesMu.Unlock()
+	return s.IncludePrereleases || includePrereleases
+}
+
 // Load loads all source definitions
 func Load(userSourcesDir string) error {
 	loadedSources = make(map[string]*Source)
@@ -130,8 +179,26 @@ func Names() []string {
 	return names
 }
 
-// ValidateVersion checks if a version string is valid for this source
+// ValidateVersion checks if a version string is valid for this source.
+// A range such as "^20.10" or ">=17 <22" is valid when the versions it
+// names are, and "lts" aliases (as found in .nvmrc) are valid for sources
+// that mark LTS releases.
 func (s *Source) ValidateVersion(version string) bool {
+	if IsChannel(version) && !strings.EqualFold(version, ChannelLatest) {
+		return s.HasLTS()
+	}
+	if semver.IsConstraint(version) {
+		c, err := semver.ParseConstraint(version)
+		if err != nil {
+			return false
+		}
+		for _, v := range c.Versions() {
+			if !s.ValidateVersion(v) {
+				return false
+			}
+		}
+		return true
+	}
 	if s.VersionRegex == "" {
 		return true
 	}
@@ -147,7 +214,11 @@ func ParseVersionAndDistribution(version string) (string, string) {
 	if len(parts) >= 2 {
 		lastPart := parts[len(parts)-1]
 		// Check if last part is a known distribution suffix
-		distSuffixes := []string{"tem", "temurin", "amzn", "corretto", "zulu", "graal", "graalce"}
+		distSuffixes := []string{
+			"tem", "temurin", "amzn", "corretto", "zulu", "graal", "graalce",
+			"librca", "liberica", "ms", "microsoft", "sapmchn", "sapmachine",
+			"sem", "semeru", "oracle",
+		}
 		for _, suffix := range distSuffixes {
 			if strings.EqualFold(lastPart, suffix) {
 				ver := strings.Join(parts[:len(parts)-1], "-")
@@ -169,6 +240,16 @@ func NormalizeDistribution(dist string) string {
 		return "zulu"
 	case "graal", "graalce":
 		return "graalce"
+	case "librca", "liberica":
+		return "liberica"
+	case "ms", "microsoft":
+		return "microsoft"
+	case "sapmchn", "sapmachine":
+		return "sapmachine"
+	case "sem", "semeru":
+		return "semeru"
+	case "oracle":
+		return "oracle"
 	default:
 		return dist
 	}
@@ -181,39 +262,59 @@ func (s *Source) GetDownloadURL(version string) string {
 
 // GetDownloadURLWithDist returns the download URL for a specific version and distribution
 func (s *Source) GetDownloadURLWithDist(version, dist string) string {
-	var url string
+	url := s.DownloadURL
+	if url == "" && s.Kind == KindMaven {
+		url = s.mavenDownloadURL()
+	}
 
 	// If distributions are available, use them
-	if len(s.Distributions) > 0 {
-		// Normalize distribution name
-		dist = NormalizeDistribution(dist)
+	dist = s.resolveDist(dist)
+	if d, ok := s.Distributions[dist]; ok {
+		url = d.DownloadURL
+	}
+
+	return s.expand(url, version, dist)
+}
+
+// resolveDist normalizes a distribution name, falling back to the default
+// distribution when none is given. Returns "" for sources without distributions.
+func (s *Source) resolveDist(dist string) string {
+	if len(s.Distributions) == 0 {
+		return ""
+	}
+
+	// Normalize distribution name
+	dist = NormalizeDistribution(dist)
 
-		// Use default distribution if none specified
+	// Use default distribution if none specified
+	if dist == "" {
+		dist = s.DefaultDist
 		if dist == "" {
-			dist = s.DefaultDist
-			if dist == "" {
-				// Fall back to first available distribution
-				for k := range s.Distributions {
-					dist = k
-					break
-				}
+			// Fall back to first available distribution
+			for k := range s.Distributions {
+				dist = k
+				break
 			}
 		}
+	}
+	return dist
+}
 
-		if d, ok := s.Distributions[dist]; ok {
-			url = d.DownloadURL
-		} else {
-			// Fall back to default URL if distribution not found
-			url = s.DownloadURL
-		}
-	} else {
-		url = s.DownloadURL
+// platformMap returns the platform naming tables for a distribution,
+// layering distribution overrides on top of the source-level tables
+func (s *Source) platformMap(dist string) *PlatformMap {
+	if d, ok := s.Distributions[dist]; ok && d.Platforms != nil {
+		return s.Platforms.merge(d.Platforms)
 	}
+	return s.Platforms
+}
 
-	url = strings.ReplaceAll(url, "{version}", version)
+// expand replaces version and platform placeholders in a URL or pattern
+func (s *Source) expand(tmpl, version, dist string) string {
+	tmpl = strings.ReplaceAll(tmpl, "{version}", version)
 	// Handle version without dots (e.g., "21" for Java)
-	url = strings.ReplaceAll(url, "{majorVersion}", strings.Split(version, ".")[0])
-	return url
+	tmpl = strings.ReplaceAll(tmpl, "{majorVersion}", strings.Split(version, ".")[0])
+	return s.platformMap(dist).replacePlatform(tmpl, targetPlatform)
 }
 
 // GetDistributionDisplayName returns the display name for a distribution
@@ -230,16 +331,17 @@ func (s *Source) GetExtractPattern(version string) string {
 	if s.ExtractPattern == "" {
 		return ""
 	}
-	pattern := s.ExtractPattern
-	pattern = strings.ReplaceAll(pattern, "{version}", version)
-	pattern = strings.ReplaceAll(pattern, "{majorVersion}", strings.Split(version, ".")[0])
-	return pattern
+	return s.expand(s.ExtractPattern, version, s.resolveDist(""))
 }
 
-// ResolveVersion resolves a partial version to a full version
-// e.g., "20" -> "20.18.0" for Node.js
+// ResolveVersion resolves a partial version, range or channel alias to a
+// full version e.g., "20" -> "20.18.0", "^20.10" -> "20.18.0",
+// "lts/iron" -> "20.18.0" for Node.js
 func (s *Source) ResolveVersion(partial string) (string, error) {
-	if s.ReleasesURL == "" {
+	if IsChannel(partial) {
+		return s.resolveChannel(partial)
+	}
+	if s.listingURL() == "" && !semver.IsConstraint(partial) {
 		// No releases URL, assume version is complete
 		return partial, nil
 	}
@@ -247,8 +349,9 @@ func (s *Source) ResolveVersion(partial string) (string, error) {
 	versions, err := s.FetchVersions()
 	if err != nil {
 		// If we can't fetch versions, return error for partial versions
-		// but allow exact-looking versions through
-		if looksLikePartialVersion(partial) {
+		// but allow exact-looking versions through. Offline, a missing
+		// listing is always reported rather than guessed around.
+		if looksLikePartialVersion(partial) || semver.IsConstraint(partial) || errors.Is(err, ErrNotCached) {
 			return "", fmt.Errorf("could not fetch available versions to resolve %s: %w", partial, err)
 		}
 		return partial, nil
@@ -268,10 +371,140 @@ func (s *Source) ResolveVersion(partial string) (string, error) {
 		}
 	}
 
+	if semver.IsConstraint(partial) {
+		return s.matchConstraint(partial, versions)
+	}
+
 	// Find best matching version
 	return s.findBestMatch(partial, versions)
 }
 
+// matchConstraint returns the newest version satisfying a range. When none
+// does, the error lists the available versions nearest to it.
+func (s *Source) matchConstraint(expr string, versions []string) (string, error) {
+	c, err := semver.ParseConstraint(expr)
+	if err != nil {
+		return "", err
+	}
+
+	var matches []string
+	for _, v := range versions {
+		if c.Check(v) {
+			matches = append(matches, v)
+		}
+	}
+	if len(matches) > 0 {
+		semver.Sort(matches)
+		return matches[0], nil
+	}
+
+	nearest := semver.Nearest(versions, c, 5)
+	if len(nearest) == 0 {
+		return "", fmt.Errorf("no %s version satisfies %s", s.DisplayName, expr)
+	}
+	return "", fmt.Errorf("no %s version satisfies %s (nearest available: %s)", s.DisplayName, expr, strings.Join(nearest, ", "))
+}
+
+// ResolveVersionWithDist resolves a partial version, range or channel alias
+// against a specific distribution.
+// Distributions backed by a vendor API resolve to a concrete build
+// (e.g., "21.0" -> "21.0.2"); a bare major version keeps using the "latest" URL
+// template when the distribution has one.
+func (s *Source) ResolveVersionWithDist(partial, dist string) (string, error) {
+	partial = strings.TrimPrefix(partial, "v")
+	if IsChannel(partial) {
+		// Java channels name a major version ("lts" -> "21"), resolved below
+		major, err := s.resolveChannel(partial)
+		if err != nil {
+			return "", err
+		}
+		partial = major
+	}
+	if !s.HasReleaseAPI(dist) {
+		return s.ResolveVersion(partial)
+	}
+	if semver.IsConstraint(partial) {
+		return s.resolveConstraintWithDist(partial, dist)
+	}
+	bareMajor := !strings.Contains(stripWildcard(partial), ".")
+	if bareMajor && s.hasLatestURL(dist) {
+		return s.ResolveVersion(partial)
+	}
+
+	releases, err := s.FetchReleases(dist, strings.Split(partial, ".")[0])
+	if err != nil {
+		return "", err
+	}
+
+	versions := make([]string, 0, len(releases))
+	for _, r := range releases {
+		versions = append(versions, r.Version)
+	}
+
+	resolved, err := s.findBestMatch(partial, versions)
+	if err != nil {
+		return "", err
+	}
+
+	// findBestMatch lets unknown complete-looking versions through; vendor
+	// APIs are authoritative, so refuse versions they do not list
+	for _, v := range versions {
+		if v == resolved {
+			return resolved, nil
+		}
+	}
+	return "", fmt.Errorf("%s %s is not available from %s", s.DisplayName, partial, s.GetDistributionDisplayName(s.resolveDist(dist)))
+}
+
+// resolveConstraintWithDist resolves a range against a vendor API, which
+// lists builds per major version: the majors the range allows are tried
+// newest first.
+func (s *Source) resolveConstraintWithDist(expr, dist string) (string, error) {
+	c, err := semver.ParseConstraint(expr)
+	if err != nil {
+		return "", err
+	}
+	majors, err := s.FetchVersions()
+	if err != nil {
+		return "", fmt.Errorf("could not fetch available versions to resolve %s: %w", expr, err)
+	}
+	semver.Sort(majors)
+
+	var seen []string
+	for _, major := range majors {
+		v := semver.Parse(major)
+		if len(v.Release) == 0 || !c.AllowsMajor(v.Release[0]) {
+			continue
+		}
+		releases, err := s.FetchReleases(dist, strconv.Itoa(v.Release[0]))
+		if err != nil {
+			return "", err
+		}
+		for _, r := range releases {
+			if c.Check(r.Version) {
+				return r.Version, nil
+			}
+			seen = append(seen, r.Version)
+		}
+	}
+	if len(seen) == 0 {
+		seen = majors
+	}
+	return s.matchConstraint(expr, seen)
+}
+
+// listingURL returns the URL versions are listed from, "" if the source
+// has no listing and versions are taken as given
+func (s *Source) listingURL() string {
+	switch {
+	case s.Kind == KindMaven && s.ReleasesURL == "":
+		return s.mavenMetadataURL()
+	case s.Kind == KindGitHub && s.ReleasesURL != "":
+		return githubFirstPage(s.ReleasesURL)
+	}
+	return s.ReleasesURL
+}
+
 // isWildcardVersion checks if version ends with .x, .X, or .*
 func isWildcardVersion(v string) bool {
 	v = strings.ToLower(v)
@@ -291,6 +524,11 @@ func stripWildcard(v string) string {
 func looksLikePartialVersion(v string) bool {
 	v = strings.TrimPrefix(v, "v")
 
+	// Ranges always need resolving
+	if semver.IsConstraint(v) {
+		return true
+	}
+
 	// Wildcard patterns are always partial
 	if isWildcardVersion(v) {
 		return true
@@ -298,6 +536,11 @@ func looksLikePartialVersion(v string) bool {
 
 	parts := strings.Split(v, ".")
 
+	// An explicit prerelease ("8.5-rc-1", "3.3.0-RC1") names one build
+	if semver.IsPrerelease(v) {
+		return false
+	}
+
 	// Single number (like "21" for Java) - could be complete for some tools
 	if len(parts) == 1 {
 		return false // Allow single numbers through (Java uses major versions)
@@ -314,89 +557,220 @@ func looksLikePartialVersion(v string) bool {
 
 // FetchVersions fetches available versions from the releases URL
 func (s *Source) FetchVersions() ([]string, error) {
-	var versions []string
-
-	// Start with static versions if available
-	if len(s.StaticVersions) > 0 {
-		versions = append(versions, s.StaticVersions...)
+	releases, err := s.ListReleases()
+	if err != nil {
+		return nil, err
 	}
+	versions := make([]string, 0, len(releases))
+	for _, r := range releases {
+		versions = append(versions, r.Version)
+	}
+	return versions, nil
+}
 
-	// Fetch from API if URL is configured
-	if s.ReleasesURL != "" {
-		resp, err := httpClient.Get(s.ReleasesURL)
-		if err != nil {
-			// If we have static versions, return those even if API fails
-			if len(versions) > 0 {
-				return versions, nil
-			}
-			return nil, err
-		}
-		defer func() { _ = resp.Body.Close() }()
+// ListReleases is FetchVersions with the per-version metadata the listing
+// carries: LTS flag or codename and release date. Download URL and checksum
+// are filled in for listings with releaseFields.
+func (s *Source) ListReleases() ([]Release, error) {
+	var releases []Release
 
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			if len(versions) > 0 {
-				return versions, nil
-			}
-			return nil, err
+	// Start with static versions if available
+	for _, v := range s.StaticVersions {
+		releases = append(releases, Release{Version: v})
+	}
+
+	var listed []Release
+	var err error
+	switch {
+	case s.Kind == KindFoojay && s.ReleasesURL != "":
+		// Disco API sources list major versions in their own format
+		listed, err = fetchFoojayMajorVersions(s.ReleasesURL)
+	case s.Kind == KindGitHub && s.ReleasesURL != "":
+		listed, err = s.fetchGitHubVersions()
+	case s.Kind == KindMaven:
+		listed, err = versionReleases(s.fetchMavenVersions())
+	case s.Kind == KindIndex && s.ReleasesURL != "":
+		listed, err = versionReleases(s.fetchIndexVersions())
+	case s.ReleasesURL != "":
+		// Fetch from API if URL is configured
+		var body []byte
+		body, err = fetchMetadata(s.ReleasesURL, "")
+		if err == nil {
+			listed, err = s.parseReleases(body)
 		}
-
-		apiVersions, err := s.parseVersions(body)
-		if err != nil {
-			if len(versions) > 0 {
-				return versions, nil
+	}
+	if err != nil {
+		// If we have static versions, return those even if API fails; a
+		// rate limit is worth knowing about, as it hides every newer release
+		if len(releases) > 0 {
+			var rl *RateLimitError
+			if errors.As(err, &rl) {
+				fmt.Fprintf(os.Stderr, "Warning: %v; listing only %s's built-in versions\n", err, s.DisplayName)
 			}
-			return nil, err
+			return releases, nil
 		}
-		versions = append(versions, apiVersions...)
+		return nil, err
 	}
+	releases = append(releases, listed...)
 
 	// Remove duplicates
 	seen := make(map[string]bool)
-	unique := make([]string, 0, len(versions))
-	for _, v := range versions {
-		if !seen[v] {
-			seen[v] = true
-			unique = append(unique, v)
+	unique := make([]Release, 0, len(releases))
+	for _, r := range releases {
+		if seen[r.Version] {
+			continue
 		}
+		if !s.IncludesPrereleases() && semver.IsPrerelease(r.Version) {
+			continue
+		}
+		seen[r.Version] = true
+		unique = append(unique, r)
 	}
-
 	return unique, nil
 }
 
-func (s *Source) parseVersions(data []byte) ([]string, error) {
-	var versions []string
+// versionReleases wraps a plain version list from listings without metadata
+func versionReleases(versions []string, err error) ([]Release, error) {
+	if err != nil {
+		return nil, err
+	}
+	releases := make([]Release, 0, len(versions))
+	for _, v := range versions {
+		releases = append(releases, Release{Version: v})
+	}
+	return releases, nil
+}
+
+// parseReleases reads the release list from a JSON listing, selecting entries
+// with releasesPath and per-version data with releaseFields
+func (s *Source) parseReleases(data []byte) ([]Release, error) {
+	var root interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("parsing release list: %w", err)
+	}
+
+	items, err := s.releaseItems(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]*jsonPath
+	if s.ReleaseFields != nil {
+		fields = make(map[string]*jsonPath)
+		for name, expr := range map[string]string{
+			"url":      s.ReleaseFields.URL,
+			"checksum": s.ReleaseFields.Checksum,
+			"lts":      s.ReleaseFields.LTS,
+			"date":     s.ReleaseFields.Date,
+		} {
+			if expr == "" {
+				continue
+			}
+			path, err := compileJSONPath(s.platformMap("").replacePlatform(expr, targetPlatform))
+			if err != nil {
+				return nil, fmt.Errorf("%s: releaseFields.%s: %w", s.Name, name, err)
+			}
+			fields[name] = path
+		}
+	}
+
+	var releases []Release
+	for _, item := range items {
+		v := s.extractVersion(item)
+		if v == "" {
+			continue
+		}
+		r := Release{Version: v}
+		if path, ok := fields["url"]; ok {
+			r.URL = s.resolveURL(stringValue(path.first(item)))
+		}
+		if path, ok := fields["checksum"]; ok {
+			r.Checksum = strings.ToLower(stringValue(path.first(item)))
+		}
+		if path, ok := fields["lts"]; ok {
+			r.LTS = stringValue(path.first(item))
+			if r.LTS == "false" {
+				r.LTS = ""
+			}
+		}
+		if path, ok := fields["date"]; ok {
+			r.Date = releaseDate(stringValue(path.first(item)))
+		}
+		releases = append(releases, r)
+	}
+	return releases, nil
+}
 
-	// Try parsing as array first
-	var arr []interface{}
-	if err := json.Unmarshal(data, &arr); err == nil {
-		for _, item := range arr {
-			if v := s.extractVersion(item); v != "" {
-				versions = append(versions, v)
+// releaseItems returns the release entries of a listing. Without a
+// releasesPath, the listing is either an array of entries or an object
+// holding one under a well-known field.
+func (s *Source) releaseItems(root interface{}) ([]interface{}, error) {
+	if s.ReleasesPath != "" {
+		path, err := compileJSONPath(s.ReleasesPath)
+		if err != nil {
+			return nil, fmt.Errorf("%s: releasesPath: %w", s.Name, err)
+		}
+		// A path naming the array itself ("$.versions") selects its elements
+		var items []interface{}
+		for _, v := range path.eval(root) {
+			if arr, ok := v.([]interface{}); ok {
+				items = append(items, arr...)
+			} else {
+				items = append(items, v)
 			}
 		}
-		return versions, nil
+		return items, nil
 	}
 
-	// Try parsing as object with versions field
-	var obj map[string]interface{}
-	if err := json.Unmarshal(data, &obj); err == nil {
+	switch v := root.(type) {
+	case []interface{}:
+		return v, nil
+	case map[string]interface{}:
 		// Check for common version array fields
 		for _, field := range []string{"versions", "releases", "available_releases", "available_lts_releases"} {
-			if arr, ok := obj[field].([]interface{}); ok {
-				for _, item := range arr {
-					if v := s.extractVersion(item); v != "" {
-						versions = append(versions, v)
-					}
-				}
-				if len(versions) > 0 {
-					return versions, nil
-				}
+			if arr, ok := v[field].([]interface{}); ok && len(arr) > 0 {
+				return arr, nil
 			}
 		}
 	}
+	return nil, nil
+}
 
-	return versions, nil
+// resolveURL resolves a download URL from a release listing against releasesUrl
+func (s *Source) resolveURL(ref string) string {
+	if ref == "" {
+		return ""
+	}
+	base, err := url.Parse(s.ReleasesURL)
+	if err != nil {
+		return ref
+	}
+	u, err := base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return u.String()
+}
+
+// releaseDate trims a timestamp ("2024-02-06T18:32:10Z") to its date
+func releaseDate(ts string) string {
+	if len(ts) > 10 && ts[4] == '-' && ts[7] == '-' {
+		return ts[:10]
+	}
+	return ts
+}
+
+// stringValue formats a scalar JSON value, "" for objects, arrays and null
+func stringValue(v interface{}) string {
+	switch vv := v.(type) {
+	case string:
+		return vv
+	case float64:
+		return strconv.FormatFloat(vv, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(vv)
+	}
+	return ""
 }
 
 func (s *Source) extractVersion(item interface{}) string {
@@ -412,13 +786,15 @@ func (s *Source) extractVersion(item interface{}) string {
 	case float64:
 		return fmt.Sprintf("%.0f", v)
 	case map[string]interface{}:
-		if val, ok := v[versionField]; ok {
-			switch vv := val.(type) {
-			case string:
-				ver = vv
-			case float64:
-				return fmt.Sprintf("%.0f", vv)
-			}
+		path, err := compileJSONPath(versionField)
+		if err != nil {
+			return ""
+		}
+		switch vv := path.first(v).(type) {
+		case string:
+			ver = vv
+		case float64:
+			return fmt.Sprintf("%.0f", vv)
 		}
 	}
 
@@ -479,39 +855,11 @@ func (s *Source) findBestMatch(partial string, versions []string) (string, error
 	}
 
 	// Sort by version (semantic) and return highest
-	sort.Slice(matches, func(i, j int) bool {
-		return compareVersions(matches[i], matches[j]) > 0
-	})
+	semver.Sort(matches)
 
 	return matches[0], nil
 }
 
-// compareVersions compares two version strings
-// Returns >0 if a > b, <0 if a < b, 0 if equal
-func compareVersions(a, b string) int {
-	aParts := strings.Split(a, ".")
-	bParts := strings.Split(b, ".")
-
-	maxLen := len(aParts)
-	if len(bParts) > maxLen {
-		maxLen = len(bParts)
-	}
-
-	for i := 0; i < maxLen; i++ {
-		var aNum, bNum int
-		if i < len(aParts) {
-			_, _ = fmt.Sscanf(aParts[i], "%d", &aNum)
-		}
-		if i < len(bParts) {
-			_, _ = fmt.Sscanf(bParts[i], "%d", &bNum)
-		}
-		if aNum != bNum {
-			return aNum - bNum
-		}
-	}
-	return 0
-}
-
 // GetDependencies returns the list of dependencies for this source
 func (s *Source) GetDependencies() []string {
 	return s.Dependencies
@@ -522,14 +870,9 @@ func (s *Source) GetChecksumURL(version, dist string) string {
 	var url string
 
 	// If distributions are available, check for distribution-specific checksum URL
-	if len(s.Distributions) > 0 {
-		dist = NormalizeDistribution(dist)
-		if dist == "" {
-			dist = s.DefaultDist
-		}
-		if d, ok := s.Distributions[dist]; ok && d.ChecksumURL != "" {
-			url = d.ChecksumURL
-		}
+	dist = s.resolveDist(dist)
+	if d, ok := s.Distributions[dist]; ok && d.ChecksumURL != "" {
+		url = d.ChecksumURL
 	}
 
 	// Fall back to source-level checksum URL
@@ -541,10 +884,7 @@ func (s *Source) GetChecksumURL(version, dist string) string {
 		return ""
 	}
 
-	// Replace placeholders
-	url = strings.ReplaceAll(url, "{version}", version)
-	url = strings.ReplaceAll(url, "{majorVersion}", strings.Split(version, ".")[0])
-	return url
+	return s.expand(url, version, dist)
 }
 
 // DependencyStatus represents whether a dependency is satisfied
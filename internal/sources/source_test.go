@@ -1,6 +1,7 @@
 package sources
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -37,12 +38,14 @@ func TestValidateVersion(t *testing.T) {
 		{"java", "21-amzn", true},
 		{"java", "21-zulu", true},
 		{"java", "latest", false},
+		{"java", "lts", true},
 
 		// Node versions
 		{"node", "20", true},
 		{"node", "20.10.0", true},
 		{"node", "v20.10.0", true},
-		{"node", "lts", false},
+		{"node", "lts", true}, // As found in .nvmrc
+		{"node", "lts/iron", true},
 
 		// Scala 2 versions
 		{"scala", "2.13.12", true},
@@ -63,6 +66,7 @@ func TestValidateVersion(t *testing.T) {
 		{"gradle", "8.5", true},
 		{"gradle", "8.4.1", true},
 		{"gradle", "gradle-8.5", false},
+		{"gradle", "lts", false}, // Gradle has no LTS releases
 	}
 
 	for _, tt := range tests {
@@ -108,7 +112,7 @@ func TestGetDownloadURL(t *testing.T) {
 	}
 }
 
-func TestParseVersions(t *testing.T) {
+func TestParseReleases(t *testing.T) {
 	tests := []struct {
 		name     string
 		source   *Source
@@ -143,19 +147,19 @@ func TestParseVersions(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			versions, err := tt.source.parseVersions([]byte(tt.data))
+			releases, err := tt.source.parseReleases([]byte(tt.data))
 			if err != nil {
-				t.Fatalf("parseVersions failed: %v", err)
+				t.Fatalf("parseReleases failed: %v", err)
 			}
 
-			if len(versions) != len(tt.expected) {
-				t.Errorf("expected %d versions, got %d: %v", len(tt.expected), len(versions), versions)
+			if len(releases) != len(tt.expected) {
+				t.Errorf("expected %d releases, got %d: %v", len(tt.expected), len(releases), releases)
 				return
 			}
 
-			for i, v := range versions {
-				if v != tt.expected[i] {
-					t.Errorf("version[%d]: expected %q, got %q", i, tt.expected[i], v)
+			for i, r := range releases {
+				if r.Version != tt.expected[i] {
+					t.Errorf("release[%d]: expected %q, got %q", i, tt.expected[i], r.Version)
 				}
 			}
 		})
@@ -286,6 +290,10 @@ func TestParseVersionAndDistribution(t *testing.T) {
 		{"17.0.9-tem", "17.0.9", "tem"},
 		{"21-unknown", "21-unknown", ""},  // Unknown suffix not stripped
 		{"21-beta-tem", "21-beta", "tem"}, // Multi-part version
+		{"21-librca", "21", "librca"},
+		{"17.0.10-sapmchn", "17.0.10", "sapmchn"},
+		{"21-graalce", "21", "graalce"},
+		{"21-sem", "21", "sem"},
 	}
 
 	for _, tt := range tests {
@@ -307,6 +315,12 @@ func TestNormalizeDistribution(t *testing.T) {
 		{"amzn", "corretto"},
 		{"corretto", "corretto"},
 		{"zulu", "zulu"},
+		{"librca", "liberica"},
+		{"ms", "microsoft"},
+		{"sapmchn", "sapmachine"},
+		{"graal", "graalce"},
+		{"sem", "semeru"},
+		{"oracle", "oracle"},
 		{"unknown", "unknown"},
 	}
 
@@ -405,3 +419,163 @@ func TestDependencies(t *testing.T) {
 		t.Error("Java should have no dependencies")
 	}
 }
+
+func TestPlatformPlaceholders(t *testing.T) {
+	defer SetPlatform(CurrentPlatform())
+
+	src := &Source{
+		DownloadURL:    "https://example.com/tool-{version}-{os}-{arch}.{ext}",
+		ChecksumURL:    "https://example.com/tool-{version}-{os}-{arch}.{ext}.sha256",
+		ExtractPattern: "tool-{version}-{os}-{arch}",
+		Platforms: &PlatformMap{
+			OS:   map[string]string{"windows": "win"},
+			Arch: map[string]string{"amd64": "x64"},
+		},
+	}
+
+	tests := []struct {
+		platform Platform
+		url      string
+		pattern  string
+	}{
+		{Platform{"windows", "amd64"}, "https://example.com/tool-1.0.0-win-x64.zip", "tool-1.0.0-win-x64"},
+		{Platform{"linux", "amd64"}, "https://example.com/tool-1.0.0-linux-x64.tar.gz", "tool-1.0.0-linux-x64"},
+		{Platform{"darwin", "arm64"}, "https://example.com/tool-1.0.0-darwin-arm64.tar.gz", "tool-1.0.0-darwin-arm64"},
+	}
+
+	for _, tt := range tests {
+		SetPlatform(tt.platform)
+
+		if url := src.GetDownloadURL("1.0.0"); url != tt.url {
+			t.Errorf("%s: expected URL %q, got %q", tt.platform, tt.url, url)
+		}
+		if cs := src.GetChecksumURL("1.0.0", ""); cs != tt.url+".sha256" {
+			t.Errorf("%s: expected checksum URL %q, got %q", tt.platform, tt.url+".sha256", cs)
+		}
+		if p := src.GetExtractPattern("1.0.0"); p != tt.pattern {
+			t.Errorf("%s: expected extract pattern %q, got %q", tt.platform, tt.pattern, p)
+		}
+	}
+}
+
+func TestDistributionPlatformOverrides(t *testing.T) {
+	defer SetPlatform(CurrentPlatform())
+
+	java, ok := Get("java")
+	if !ok {
+		t.Fatal("Java source not found")
+	}
+
+	tests := []struct {
+		platform Platform
+		dist     string
+		expected string
+	}{
+		{Platform{"windows", "amd64"}, "temurin", "/ga/windows/x64/jdk/"},
+		{Platform{"linux", "arm64"}, "temurin", "/ga/linux/aarch64/jdk/"},
+		{Platform{"darwin", "arm64"}, "temurin", "/ga/mac/aarch64/jdk/"},
+		{Platform{"windows", "amd64"}, "corretto", "amazon-corretto-21-x64-windows-jdk.zip"},
+		{Platform{"darwin", "amd64"}, "corretto", "amazon-corretto-21-x64-macos-jdk.tar.gz"},
+		{Platform{"windows", "amd64"}, "zulu", "-win_x64.zip"},
+		{Platform{"linux", "arm64"}, "zulu", "-linux_aarch64.tar.gz"},
+	}
+
+	for _, tt := range tests {
+		SetPlatform(tt.platform)
+		url := java.GetDownloadURLWithDist("21", tt.dist)
+		if !containsStr(url, tt.expected) {
+			t.Errorf("%s %s: URL %q should contain %q", tt.platform, tt.dist, url, tt.expected)
+		}
+	}
+}
+
+func TestParsePlatform(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Platform
+		wantErr  bool
+	}{
+		{"linux/amd64", Platform{"linux", "amd64"}, false},
+		{"Darwin/ARM64", Platform{"darwin", "arm64"}, false},
+		{"linux", Platform{}, true},
+		{"linux/", Platform{}, true},
+		{"a/b/c", Platform{}, true},
+	}
+
+	for _, tt := range tests {
+		p, err := ParsePlatform(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePlatform(%q): unexpected error state: %v", tt.input, err)
+			continue
+		}
+		if p != tt.expected {
+			t.Errorf("ParsePlatform(%q): expected %v, got %v", tt.input, tt.expected, p)
+		}
+	}
+}
+
+func TestPrereleaseOrdering(t *testing.T) {
+	src := &Source{Name: "scala3-pre"}
+	versions := []string{"3.3.0-RC1", "3.3.0", "3.3.1-RC2", "3.2.2", "3.3.0-RC3"}
+
+	// A release beats its own candidates
+	resolved, err := src.findBestMatch("3.3", []string{"3.3.0-RC1", "3.3.0", "3.3.0-RC3"})
+	if err != nil || resolved != "3.3.0" {
+		t.Errorf("findBestMatch(3.3): expected 3.3.0, got %q, %v", resolved, err)
+	}
+
+	// Prereleases are only picked once included in the list
+	resolved, _ = src.findBestMatch("3.3", versions)
+	if resolved != "3.3.1-RC2" {
+		t.Errorf("findBestMatch(3.3) with prereleases: expected 3.3.1-RC2, got %q", resolved)
+	}
+
+	// An explicit prerelease is complete, not a partial version
+	for _, v := range []string{"8.5-rc-1", "3.3.0-RC1", "1.23rc1"} {
+		if looksLikePartialVersion(v) {
+			t.Errorf("%s should not look partial", v)
+		}
+	}
+}
+
+func TestFetchVersionsHidesPrereleases(t *testing.T) {
+	src := &Source{Name: "gradle-pre", StaticVersions: []string{"8.6-rc-1", "8.5", "8.5-milestone-2", "8.4"}}
+	versions, _ := src.FetchVersions()
+	if len(versions) != 2 {
+		t.Errorf("Expected only 8.5 and 8.4, got %v", versions)
+	}
+
+	SetIncludePrereleases(true)
+	defer SetIncludePrereleases(false)
+	versions, _ = src.FetchVersions()
+	if len(versions) != 4 {
+		t.Errorf("Expected prereleases with SetIncludePrereleases, got %v", versions)
+	}
+}
+
+func TestResolveConstraint(t *testing.T) {
+	src := &Source{Name: "node-range", DisplayName: "Node.js", StaticVersions: []string{"22.1.0", "20.18.0", "20.10.0", "20.9.0", "18.19.0"}}
+
+	tests := map[string]string{
+		"^20.10":       "20.18.0",
+		"~20.9":        "20.9.0",
+		">=18 <20":     "18.19.0",
+		"^18 || ^22":   "22.1.0",
+		"20.9 - 20.10": "20.10.0",
+	}
+	for expr, want := range tests {
+		got, err := src.ResolveVersion(expr)
+		if err != nil {
+			t.Errorf("ResolveVersion(%q): %v", expr, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ResolveVersion(%q) = %s, want %s", expr, got, want)
+		}
+	}
+
+	_, err := src.ResolveVersion("^19")
+	if err == nil || !strings.Contains(err.Error(), "nearest available: 22.1.0, 20.18.0, 20.10.0, 20.9.0, 18.19.0") {
+		t.Errorf("Expected error listing nearest versions, got %v", err)
+	}
+}
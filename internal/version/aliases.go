@@ -0,0 +1,97 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/azdren/verman/internal/languages"
+	"github.com/azdren/verman/internal/sources"
+)
+
+// aliasNameRegex keeps alias names distinct from versions: they start with
+// a letter, so "17" or "^20" can never be an alias
+var aliasNameRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.-]*$`)
+
+// SetAlias points a user-defined alias at a version, e.g. "work" -> "17-amzn".
+// The target may be an installed version, a range or a channel alias; it is
+// resolved whenever the alias is used.
+func (m *Manager) SetAlias(langName, name, version string) error {
+	lang, ok := languages.Get(langName)
+	if !ok {
+		return fmt.Errorf("unknown language: %s", langName)
+	}
+	if !aliasNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid alias name %q: use letters, digits, '.', '_' and '-', starting with a letter", name)
+	}
+	if sources.IsChannel(name) || name == "current" {
+		return fmt.Errorf("%q is reserved and cannot be an alias", name)
+	}
+	if _, dist := sources.ParseVersionAndDistribution(name); dist != "" {
+		return fmt.Errorf("alias %q ends in a distribution suffix", name)
+	}
+	if version == "" {
+		return fmt.Errorf("alias %s needs a version", name)
+	}
+	if _, ok := m.Config.Alias(langName, version); ok {
+		return fmt.Errorf("%s is itself an alias; point %s at a version", version, name)
+	}
+	expr, _ := sources.ParseVersionAndDistribution(version)
+	if !strings.EqualFold(expr, sources.ChannelLatest) && !lang.ValidateVersion(expr) {
+		return fmt.Errorf("invalid %s version for alias %s: %s", langName, name, version)
+	}
+	return m.Config.SetAlias(langName, name, version)
+}
+
+// RemoveAlias deletes a user-defined alias
+func (m *Manager) RemoveAlias(langName, name string) error {
+	return m.Config.RemoveAlias(langName, name)
+}
+
+// ExpandAlias returns the version a user-defined alias points at, or the
+// version unchanged when it is not an alias
+func (m *Manager) ExpandAlias(langName, version string) string {
+	if target, ok := m.Config.Alias(langName, version); ok {
+		return target
+	}
+	return version
+}
+
+// AliasesByVersion maps installed versions to the aliases that resolve to
+// them, for listing. Aliases whose target is not installed are left out.
+func (m *Manager) AliasesByVersion(langName string) map[string][]string {
+	result := make(map[string][]string)
+	for name, target := range m.Config.Languages[langName].Aliases {
+		if needsReleaseList(target) {
+			continue
+		}
+		resolved, err := m.ResolveInstalled(langName, name)
+		if err != nil || !m.isInstalled(langName, resolved) {
+			continue
+		}
+		result[resolved] = append(result[resolved], name)
+	}
+	for _, names := range result {
+		sort.Strings(names)
+	}
+	return result
+}
+
+// isInstalled reports whether a version folder exists
+func (m *Manager) isInstalled(langName, version string) bool {
+	installed, _ := m.ListInstalled(langName)
+	for _, v := range installed {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// needsReleaseList reports whether resolving a target needs the remote
+// release list ("lts" channels), which listing should not wait for
+func needsReleaseList(target string) bool {
+	expr, _ := sources.ParseVersionAndDistribution(target)
+	return sources.IsChannel(expr) && !strings.EqualFold(expr, sources.ChannelLatest)
+}
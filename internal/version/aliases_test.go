@@ -0,0 +1,85 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetAliasValidation(t *testing.T) {
+	mgr, _ := setupTestManager(t)
+
+	for _, name := range []string{"17", "^20", "lts", "latest", "current", "work-amzn", "has space"} {
+		if err := mgr.SetAlias("java", name, "17-amzn"); err == nil {
+			t.Errorf("Expected alias name %q to be rejected", name)
+		}
+	}
+	if err := mgr.SetAlias("cobol", "work", "1.0"); err == nil {
+		t.Error("Expected error for unknown language")
+	}
+
+	if err := mgr.SetAlias("java", "work", "17-amzn"); err != nil {
+		t.Fatalf("SetAlias failed: %v", err)
+	}
+	if err := mgr.SetAlias("java", "team", "work"); err == nil {
+		t.Error("Expected error pointing an alias at another alias")
+	}
+	for _, target := range []string{"garbage", "^banana", "17.x-amzn!"} {
+		if err := mgr.SetAlias("java", "bad", target); err == nil {
+			t.Errorf("Expected invalid target %q to be rejected", target)
+		}
+	}
+	for _, target := range []string{"21", "^21", "latest", "17.0.9-tem"} {
+		if err := mgr.SetAlias("java", "ok", target); err != nil {
+			t.Errorf("SetAlias(%q) failed: %v", target, err)
+		}
+	}
+}
+
+func TestAliasResolution(t *testing.T) {
+	mgr, _ := setupTestManager(t)
+	for _, v := range []string{"17-amzn", "21.0.2", "21.0.5"} {
+		createMockVersion(t, mgr, "java", v)
+	}
+	_ = mgr.SetAlias("java", "work", "17-amzn")
+	_ = mgr.SetAlias("java", "edge", "^21")
+	_ = mgr.SetAlias("java", "old", "11")
+
+	for name, want := range map[string]string{"work": "17-amzn", "edge": "21.0.5"} {
+		got, err := mgr.ResolveInstalled("java", name)
+		if err != nil || got != want {
+			t.Errorf("ResolveInstalled(%q) = %q, %v; want %s", name, got, err, want)
+		}
+	}
+
+	byVersion := mgr.AliasesByVersion("java")
+	if strings.Join(byVersion["17-amzn"], ",") != "work" || strings.Join(byVersion["21.0.5"], ",") != "edge" {
+		t.Errorf("Unexpected aliases by version: %v", byVersion)
+	}
+	if _, ok := byVersion["11"]; ok {
+		t.Error("Aliases to versions that are not installed should not be listed")
+	}
+}
+
+func TestDetectExpandsAliases(t *testing.T) {
+	mgr, tmpDir := setupTestManager(t)
+	_ = mgr.SetAlias("java", "work", "17-amzn")
+
+	projectDir := filepath.Join(tmpDir, "project")
+	_ = os.MkdirAll(projectDir, 0755)
+	_ = os.WriteFile(filepath.Join(projectDir, ".java-version"), []byte("work\n"), 0644)
+
+	detected, err := mgr.Detect(projectDir)
+	if err != nil {
+		t.Fatalf("Detect failed: %v", err)
+	}
+	if len(detected) != 1 || detected[0].Version != "17-amzn" || detected[0].Alias != "work" {
+		t.Errorf("Expected java work -> 17-amzn, got %+v", detected)
+	}
+
+	// Without aliases the name is not a version
+	if dv := DetectForLanguage(projectDir, "java"); dv != nil {
+		t.Errorf("Expected no detection without aliases, got %+v", dv)
+	}
+}
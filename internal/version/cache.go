@@ -0,0 +1,328 @@
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/azdren/verman/internal/lock"
+)
+
+// DefaultCacheMaxSize caps the download cache when the config sets no limit
+const DefaultCacheMaxSize int64 = 10 << 30 // 10 GiB
+
+// Cache is a content-addressed store of downloaded archives. Blobs are named
+// by their SHA-256 and an index maps download URLs to them, so the same file
+// is reused across reinstalls and version roots. Blobs are copied in and out,
+// never linked, so an installed file can change without touching the cache.
+type Cache struct {
+	Dir     string
+	MaxSize int64 // Total size cap, least recently used entries are evicted first; 0 for no limit
+}
+
+// CacheEntry describes one cached download
+type CacheEntry struct {
+	URL          string    `json:"url"`
+	SHA256       string    `json:"sha256"`
+	Size         int64     `json:"size"`
+	ContentType  string    `json:"content_type,omitempty"`
+	ETag         string    `json:"etag,omitempty"`          // Validators for revalidating
+	LastModified string    `json:"last_modified,omitempty"` // downloads without a checksum
+	Added        time.Time `json:"added"`
+	LastUsed     time.Time `json:"last_used"`
+}
+
+// DownloadMeta is what the server reported about a download
+type DownloadMeta struct {
+	ContentType  string
+	ETag         string
+	LastModified string
+}
+
+type cacheIndex struct {
+	Entries []CacheEntry `json:"entries"`
+}
+
+// NewCache returns a cache rooted at dir
+func NewCache(dir string, maxSize int64) *Cache {
+	return &Cache{Dir: dir, MaxSize: maxSize}
+}
+
+// DownloadCache returns the shared download cache configured for this manager
+func (m *Manager) DownloadCache() *Cache {
+	maxSize := DefaultCacheMaxSize
+	if m.Config.CacheMaxSize > 0 {
+		maxSize = m.Config.CacheMaxSize
+	}
+	return NewCache(m.Config.CacheDir(), maxSize)
+}
+
+func (c *Cache) blobPath(sha string) string {
+	return filepath.Join(c.Dir, "blobs", sha)
+}
+
+// Lookup finds a cached download by expected checksum or, failing that, by URL.
+// It returns the entry and the path of its blob, or nil if nothing is cached.
+func (c *Cache) Lookup(url, sha string) (*CacheEntry, string, error) {
+	var found *CacheEntry
+	err := c.update(func(idx *cacheIndex) {
+		for i := range idx.Entries {
+			e := &idx.Entries[i]
+			match := e.URL == url
+			if sha != "" {
+				// A known checksum is authoritative: same content from any URL
+				// is a hit, and a stale file at the same URL is not
+				match = strings.EqualFold(e.SHA256, sha)
+			}
+			if !match {
+				continue
+			}
+			if _, err := os.Stat(c.blobPath(e.SHA256)); err != nil {
+				continue
+			}
+			e.LastUsed = time.Now()
+			entry := *e
+			found = &entry
+			return
+		}
+	})
+	if err != nil || found == nil {
+		return nil, "", err
+	}
+	return found, c.blobPath(found.SHA256), nil
+}
+
+// Store adds a downloaded file to the cache under url, then evicts least
+// recently used entries beyond the size cap
+func (c *Cache) Store(url, path string, meta DownloadMeta) (*CacheEntry, error) {
+	sha, err := calculateSHA256(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	blob := c.blobPath(sha)
+	if err := os.MkdirAll(filepath.Dir(blob), 0755); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(blob); err != nil {
+		if err := copyFile(path, blob); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	entry := CacheEntry{
+		URL:          url,
+		SHA256:       sha,
+		Size:         info.Size(),
+		ContentType:  meta.ContentType,
+		ETag:         meta.ETag,
+		LastModified: meta.LastModified,
+		Added:        now,
+		LastUsed:     now,
+	}
+	err = c.update(func(idx *cacheIndex) {
+		// A URL whose content changed ("latest" links) replaces its old blob
+		var replaced []CacheEntry
+		kept := idx.Entries[:0]
+		for _, e := range idx.Entries {
+			if e.URL != url {
+				kept = append(kept, e)
+			} else {
+				replaced = append(replaced, e)
+			}
+		}
+		idx.Entries = append(kept, entry)
+		c.removeBlobs(idx, replaced)
+		c.evict(idx, url)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Entries lists cached downloads, most recently used first
+func (c *Cache) Entries() ([]CacheEntry, error) {
+	idx, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+	entries := idx.Entries
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LastUsed.After(entries[j].LastUsed)
+	})
+	return entries, nil
+}
+
+// Clean removes entries not used within olderThan (all entries when zero).
+// Returns the number of entries removed and bytes freed.
+func (c *Cache) Clean(olderThan time.Duration) (int, int64, error) {
+	removed := 0
+	var freed int64
+	err := c.update(func(idx *cacheIndex) {
+		cutoff := time.Now().Add(-olderThan)
+		kept := idx.Entries[:0]
+		var dropped []CacheEntry
+		for _, e := range idx.Entries {
+			if olderThan == 0 || e.LastUsed.Before(cutoff) {
+				dropped = append(dropped, e)
+				continue
+			}
+			kept = append(kept, e)
+		}
+		idx.Entries = kept
+		removed = len(dropped)
+		freed = c.removeBlobs(idx, dropped)
+	})
+	return removed, freed, err
+}
+
+// evict drops least recently used entries until the cache fits MaxSize.
+// The entry for keepURL (just stored) is never evicted.
+func (c *Cache) evict(idx *cacheIndex, keepURL string) {
+	if c.MaxSize <= 0 {
+		return
+	}
+
+	sort.Slice(idx.Entries, func(i, j int) bool {
+		return idx.Entries[i].LastUsed.Before(idx.Entries[j].LastUsed)
+	})
+
+	// Several URLs may share a blob; it only frees space once all are gone
+	refs := make(map[string]int)
+	var total int64
+	for _, e := range idx.Entries {
+		if refs[e.SHA256] == 0 {
+			total += e.Size
+		}
+		refs[e.SHA256]++
+	}
+
+	var dropped []CacheEntry
+	kept := idx.Entries[:0]
+	for _, e := range idx.Entries {
+		if total > c.MaxSize && e.URL != keepURL {
+			dropped = append(dropped, e)
+			if refs[e.SHA256]--; refs[e.SHA256] == 0 {
+				total -= e.Size
+			}
+			continue
+		}
+		kept = append(kept, e)
+	}
+	idx.Entries = kept
+	c.removeBlobs(idx, dropped)
+}
+
+// removeBlobs deletes the blobs of dropped entries that no remaining entry
+// references, returning the bytes freed
+func (c *Cache) removeBlobs(idx *cacheIndex, dropped []CacheEntry) int64 {
+	inUse := make(map[string]bool)
+	for _, e := range idx.Entries {
+		inUse[e.SHA256] = true
+	}
+
+	var freed int64
+	for _, e := range dropped {
+		if inUse[e.SHA256] {
+			continue
+		}
+		inUse[e.SHA256] = true // Count shared blobs once
+		if err := os.Remove(c.blobPath(e.SHA256)); err == nil {
+			freed += e.Size
+		}
+	}
+	return freed
+}
+
+// load reads the index. It is replaced atomically, so no lock is needed to read it.
+func (c *Cache) load() (*cacheIndex, error) {
+	idx := &cacheIndex{}
+	data, err := os.ReadFile(filepath.Join(c.Dir, "index.json"))
+	if os.IsNotExist(err) {
+		return idx, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, idx); err != nil {
+		return nil, fmt.Errorf("corrupt cache index: %w", err)
+	}
+	return idx, nil
+}
+
+// update applies fn to the index under the cache lock and saves it
+func (c *Cache) update(fn func(idx *cacheIndex)) error {
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return err
+	}
+
+	l, err := lock.Acquire(filepath.Join(c.Dir, "index.lock"))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = l.Release() }()
+
+	idx, err := c.load()
+	if err != nil {
+		// A damaged index only loses bookkeeping; start over rather than
+		// making every download fail
+		idx = &cacheIndex{}
+	}
+	fn(idx)
+
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.Dir, ".index-*.json")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, filepath.Join(c.Dir, "index.json"))
+}
+
+// copyFile copies src to dst through a temp file, so dst is either
+// complete or absent
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	tmp := dst + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
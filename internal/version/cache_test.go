@@ -0,0 +1,269 @@
+package version
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDownloadWithRetry_UsesCache(t *testing.T) {
+	content := []byte("jdk archive bytes")
+	hash := sha256.Sum256(content)
+	checksum := hex.EncodeToString(hash[:])
+
+	var requests, revalidations int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead && r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&revalidations, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	cache := NewCache(filepath.Join(tmpDir, "cache"), 0)
+
+	download := func(dest, sha string) *DownloadResult {
+		t.Helper()
+		cfg := DefaultDownloadConfig()
+		cfg.URL = server.URL + "/jdk.zip"
+		cfg.DestPath = filepath.Join(tmpDir, dest)
+		cfg.ExpectedChecksum = sha
+		cfg.Cache = cache
+		result, err := DownloadWithRetry(cfg)
+		if err != nil {
+			t.Fatalf("DownloadWithRetry failed: %v", err)
+		}
+		data, _ := os.ReadFile(cfg.DestPath)
+		if string(data) != string(content) {
+			t.Fatalf("Unexpected content in %s: %q", dest, data)
+		}
+		return result
+	}
+
+	if result := download("first", ""); result.FromCache {
+		t.Error("First download should not come from the cache")
+	}
+
+	// Same URL, and same checksum, both hit without another request
+	for _, sha := range []string{"", checksum} {
+		result := download("again-"+sha, sha)
+		if !result.FromCache {
+			t.Errorf("Expected cache hit (checksum %q)", sha)
+		}
+		if result.ContentType != "application/zip" {
+			t.Errorf("Expected cached Content-Type, got %q", result.ContentType)
+		}
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("Expected exactly 1 HTTP request, got %d", n)
+	}
+	// Only the hit without a checksum asked the server first
+	if n := atomic.LoadInt32(&revalidations); n != 1 {
+		t.Errorf("Expected 1 revalidation, got %d", n)
+	}
+
+	// The install gets its own copy: changing it leaves the cache intact
+	_ = os.WriteFile(filepath.Join(tmpDir, "first"), []byte("edited"), 0644)
+	if data, _ := os.ReadFile(cache.blobPath(checksum)); string(data) != string(content) {
+		t.Errorf("Editing a download changed the cached blob: %q", data)
+	}
+
+	// A corrupted blob is detected and downloaded again
+	_ = os.WriteFile(cache.blobPath(checksum), []byte("tampered"), 0644)
+	if result := download("repaired", ""); result.FromCache {
+		t.Error("Corrupted cache entry should not be used")
+	}
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("Expected a fresh download after corruption, got %d requests", n)
+	}
+}
+
+func TestDownloadWithRetry_RevalidatesMutableURL(t *testing.T) {
+	body := "latest v1"
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		modified := "Mon, 01 Jan 2024 00:00:00 GMT"
+		if body != "latest v1" {
+			modified = "Tue, 02 Jan 2024 00:00:00 GMT"
+		}
+		if r.Header.Get("If-Modified-Since") == modified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", modified)
+		if r.Method == http.MethodHead {
+			return
+		}
+		atomic.AddInt32(&requests, 1)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	cache := NewCache(filepath.Join(tmpDir, "cache"), 0)
+	download := func(dest string) *DownloadResult {
+		t.Helper()
+		cfg := DefaultDownloadConfig()
+		cfg.URL = server.URL + "/binary/latest/21"
+		cfg.DestPath = filepath.Join(tmpDir, dest)
+		cfg.Cache = cache
+		result, err := DownloadWithRetry(cfg)
+		if err != nil {
+			t.Fatalf("DownloadWithRetry failed: %v", err)
+		}
+		if data, _ := os.ReadFile(cfg.DestPath); string(data) != body {
+			t.Fatalf("Expected %q in %s, got %q", body, dest, data)
+		}
+		return result
+	}
+
+	download("first")
+	if result := download("unchanged"); !result.FromCache {
+		t.Error("Expected a cache hit while the server reports no change")
+	}
+
+	// The URL now serves a newer build: the stale entry must not be used
+	body = "latest v2"
+	if result := download("changed"); result.FromCache {
+		t.Error("Expected a fresh download after the URL changed")
+	}
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("Expected 2 full downloads, got %d", n)
+	}
+}
+
+func TestCacheLookupByChecksum(t *testing.T) {
+	tmpDir := t.TempDir()
+	cache := NewCache(filepath.Join(tmpDir, "cache"), 0)
+
+	file := filepath.Join(tmpDir, "go.tar.gz")
+	_ = os.WriteFile(file, []byte("go toolchain"), 0644)
+	entry, err := cache.Store("https://go.dev/dl/go1.22.0.tar.gz", file, DownloadMeta{})
+	if err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	// Mirror URL with the same content is a hit
+	if hit, _, _ := cache.Lookup("https://mirror.example.com/go1.22.0.tar.gz", entry.SHA256); hit == nil {
+		t.Error("Expected lookup by checksum to hit")
+	}
+	// Same URL but a different expected checksum is a miss
+	if hit, _, _ := cache.Lookup("https://go.dev/dl/go1.22.0.tar.gz", "0000"); hit != nil {
+		t.Error("Expected checksum mismatch to miss")
+	}
+}
+
+func TestCacheStoreReplacesChangedURL(t *testing.T) {
+	tmpDir := t.TempDir()
+	cache := NewCache(filepath.Join(tmpDir, "cache"), 0)
+
+	// A "latest" URL serving a new build replaces the old blob
+	file := filepath.Join(tmpDir, "latest.zip")
+	for _, body := range []string{"build 1", "build 2"} {
+		_ = os.WriteFile(file, []byte(body), 0644)
+		if _, err := cache.Store("https://example.com/latest.zip", file, DownloadMeta{}); err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+	}
+
+	blobs, err := os.ReadDir(filepath.Join(cache.Dir, "blobs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blobs) != 1 {
+		t.Errorf("blobs = %d, want 1", len(blobs))
+	}
+	if entries, _ := cache.Entries(); len(entries) != 1 {
+		t.Errorf("entries = %d, want 1", len(entries))
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	tmpDir := t.TempDir()
+	cache := NewCache(filepath.Join(tmpDir, "cache"), 25)
+
+	store := func(name, body string) *CacheEntry {
+		t.Helper()
+		file := filepath.Join(tmpDir, name)
+		_ = os.WriteFile(file, []byte(body), 0644)
+		entry, err := cache.Store("https://example.com/"+name, file, DownloadMeta{})
+		if err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond) // Distinct LastUsed times
+		return entry
+	}
+
+	a := store("a", "0123456789")
+	store("b", "abcdefghij")
+
+	// Touch "a" so "b" becomes least recently used
+	if hit, _, _ := cache.Lookup("https://example.com/a", ""); hit == nil {
+		t.Fatal("Expected hit for a")
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	store("c", "ABCDEFGHIJ") // 30 bytes > 25, evicts "b"
+
+	entries, err := cache.Entries()
+	if err != nil {
+		t.Fatalf("Entries failed: %v", err)
+	}
+	var urls []string
+	for _, e := range entries {
+		urls = append(urls, e.URL)
+	}
+	if len(entries) != 2 || entries[0].URL != "https://example.com/c" || entries[1].URL != "https://example.com/a" {
+		t.Errorf("Expected c and a to remain (most recent first), got %v", urls)
+	}
+	if _, err := os.Stat(cache.blobPath(a.SHA256)); err != nil {
+		t.Errorf("Expected blob of a to remain: %v", err)
+	}
+}
+
+func TestCacheClean(t *testing.T) {
+	tmpDir := t.TempDir()
+	cache := NewCache(filepath.Join(tmpDir, "cache"), 0)
+
+	for _, name := range []string{"old", "new"} {
+		file := filepath.Join(tmpDir, name)
+		_ = os.WriteFile(file, []byte(name), 0644)
+		if _, err := cache.Store("https://example.com/"+name, file, DownloadMeta{}); err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+	}
+
+	// Age the "old" entry
+	_ = cache.update(func(idx *cacheIndex) {
+		for i := range idx.Entries {
+			if idx.Entries[i].URL == "https://example.com/old" {
+				idx.Entries[i].LastUsed = time.Now().Add(-48 * time.Hour)
+			}
+		}
+	})
+
+	removed, freed, err := cache.Clean(24 * time.Hour)
+	if err != nil || removed != 1 || freed != 3 {
+		t.Errorf("Expected 1 entry (3 bytes) removed, got %d (%d bytes), %v", removed, freed, err)
+	}
+
+	removed, _, err = cache.Clean(0)
+	if err != nil || removed != 1 {
+		t.Errorf("Expected clean without age to remove the rest, got %d, %v", removed, err)
+	}
+	if entries, _ := cache.Entries(); len(entries) != 0 {
+		t.Errorf("Expected empty cache, got %v", entries)
+	}
+}
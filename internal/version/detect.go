@@ -15,14 +15,30 @@ type DetectedVersion struct {
 	Language string
 	Version  string
 	Source   string // file path that specified this version
+	Alias    string `json:",omitempty"` // user alias the file named, if any
 }
 
 // DetectAll scans the given directory for version files
 func DetectAll(dir string) ([]DetectedVersion, error) {
+	return detectAll(dir, nil)
+}
+
+// Detect scans the given directory for version files like DetectAll,
+// expanding user-defined aliases ("work" in .java-version)
+func (m *Manager) Detect(dir string) ([]DetectedVersion, error) {
+	return detectAll(dir, func(langName, version string) (string, bool) {
+		return m.Config.Alias(langName, version)
+	})
+}
+
+// aliasFunc looks up a user-defined alias
+type aliasFunc func(langName, version string) (string, bool)
+
+func detectAll(dir string, aliases aliasFunc) ([]DetectedVersion, error) {
 	var detected []DetectedVersion
 
 	for _, lang := range languages.All() {
-		if dv := detectForLanguage(dir, lang); dv != nil {
+		if dv := detectForLanguage(dir, lang, aliases); dv != nil {
 			detected = append(detected, *dv)
 		}
 	}
@@ -36,16 +52,23 @@ func DetectForLanguage(dir, langName string) *DetectedVersion {
 	if !ok {
 		return nil
 	}
-	return detectForLanguage(dir, lang)
+	return detectForLanguage(dir, lang, nil)
 }
 
-func detectForLanguage(dir string, lang languages.Language) *DetectedVersion {
+func detectForLanguage(dir string, lang languages.Language, aliases aliasFunc) *DetectedVersion {
 	for _, versionFile := range lang.VersionFiles() {
 		// Search up the directory tree
 		currentDir := dir
 		for {
 			filePath := filepath.Join(currentDir, versionFile)
 			if version := readVersionFile(filePath, lang.Name()); version != "" {
+				alias := ""
+				if aliases != nil {
+					if target, ok := aliases(lang.Name(), version); ok {
+						alias, version = version, target
+					}
+				}
+
 				// Validate version against language's regex
 				// This ensures scala 2.x goes to "scala" and 3.x goes to "scala3"
 				if lang.ValidateVersion(version) {
@@ -53,6 +76,7 @@ func detectForLanguage(dir string, lang languages.Language) *DetectedVersion {
 						Language: lang.Name(),
 						Version:  version,
 						Source:   filePath,
+						Alias:    alias,
 					}
 				}
 			}
@@ -89,6 +113,10 @@ func readVersionFile(path string, langName string) string {
 		// Rust: [toolchain] channel = "1.75.0"
 		return parseRustToolchain(content)
 
+	case "package.json":
+		// npm: {"engines": {"node": "^20.10"}}
+		return parsePackageJSON(data, langName)
+
 	case ".sdkmanrc":
 		// SDKMAN: java=17.0.9-tem
 		return parseSdkmanrc(content, langName)
@@ -114,6 +142,16 @@ func parseGlobalJson(data []byte) string {
 	return gj.SDK.Version
 }
 
+func parsePackageJSON(data []byte, langName string) string {
+	var pkg struct {
+		Engines map[string]string `json:"engines"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(pkg.Engines[langName])
+}
+
 func parseGoMod(content string) string {
 	scanner := bufio.NewScanner(strings.NewReader(content))
 	for scanner.Scan() {
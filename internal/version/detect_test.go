@@ -43,6 +43,9 @@ func TestDetectNodeVersion(t *testing.T) {
 		{"nvmrc", ".nvmrc", "20.10.0", "20.10.0"},
 		{"nvmrc with v prefix", ".nvmrc", "v18.19.0", "18.19.0"},
 		{"node-version", ".node-version", "18", "18"},
+		{"nvmrc range", ".nvmrc", "^20.10", "^20.10"},
+		{"nvmrc lts alias", ".nvmrc", "lts/iron", "lts/iron"},
+		{"package.json engines", "package.json", `{"name": "app", "engines": {"node": ">=18 <22"}}`, ">=18 <22"},
 	}
 
 	for _, tt := range tests {
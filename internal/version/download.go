@@ -1,24 +1,30 @@
 package version
 
 import (
+	"crypto/sha1"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"hash"
 	"io"
 	"net/http"
 	"os"
 	"strings"
 	"time"
+
+	"github.com/azdren/verman/internal/sources"
 )
 
 // DownloadConfig holds configuration for downloads
 type DownloadConfig struct {
-	URL            string
-	DestPath       string
-	ExpectedSHA256 string // Optional: if set, verify after download
-	MaxRetries     int
-	RetryDelay     time.Duration
-	Description    string
+	URL              string
+	DestPath         string
+	ExpectedChecksum string // Optional: SHA256 hex, or "sha1:<hex>"; verified after download
+	MaxRetries       int
+	RetryDelay       time.Duration
+	Description      string
+	Cache            *Cache // Optional: reuse and store downloads in this cache
+	Offline          bool   // Only serve from Cache, never touch the network
 }
 
 // DefaultDownloadConfig returns sensible defaults
@@ -31,15 +37,28 @@ func DefaultDownloadConfig() DownloadConfig {
 
 // DownloadResult contains information about the completed download
 type DownloadResult struct {
-	Size       int64
-	SHA256     string
-	Duration   time.Duration
-	Retries    int
-	FromResume bool
+	Size         int64
+	SHA256       string
+	ContentType  string // Content-Type reported by the server
+	ETag         string
+	LastModified string
+	Duration     time.Duration
+	Retries      int
+	FromResume   bool
+	FromCache    bool
 }
 
 // DownloadWithRetry downloads a file with retry logic and optional checksum verification
 func DownloadWithRetry(cfg DownloadConfig) (*DownloadResult, error) {
+	if cfg.Cache != nil {
+		if result := fromCache(cfg); result != nil {
+			return result, nil
+		}
+	}
+	if cfg.Offline {
+		return nil, fmt.Errorf("%w: %s has not been downloaded before", sources.ErrNotCached, cfg.URL)
+	}
+
 	var lastErr error
 	startTime := time.Now()
 
@@ -57,8 +76,8 @@ func DownloadWithRetry(cfg DownloadConfig) (*DownloadResult, error) {
 			result.Duration = time.Since(startTime)
 
 			// Verify checksum if provided
-			if cfg.ExpectedSHA256 != "" {
-				if err := verifyChecksum(cfg.DestPath, cfg.ExpectedSHA256); err != nil {
+			if cfg.ExpectedChecksum != "" {
+				if err := verifyChecksum(cfg.DestPath, cfg.ExpectedChecksum); err != nil {
 					// Checksum mismatch - delete the file and retry
 					_ = os.Remove(cfg.DestPath)
 					lastErr = err
@@ -66,6 +85,14 @@ func DownloadWithRetry(cfg DownloadConfig) (*DownloadResult, error) {
 				}
 			}
 
+			if cfg.Cache != nil {
+				meta := DownloadMeta{ContentType: result.ContentType, ETag: result.ETag, LastModified: result.LastModified}
+				if _, err := cfg.Cache.Store(cfg.URL, cfg.DestPath, meta); err != nil {
+					// Caching is best-effort; the download itself succeeded
+					fmt.Fprintf(os.Stderr, "Warning: failed to cache download: %v\n", err)
+				}
+			}
+
 			return result, nil
 		}
 
@@ -80,6 +107,76 @@ func DownloadWithRetry(cfg DownloadConfig) (*DownloadResult, error) {
 	return nil, fmt.Errorf("download failed after %d attempts: %w", cfg.MaxRetries+1, lastErr)
 }
 
+// fromCache copies a cached download to cfg.DestPath, returning nil on a miss.
+// Cached files are re-verified, so a damaged blob falls back to downloading.
+// Without an expected checksum the URL may now serve something else
+// ("latest" links), so the entry is revalidated with the server first.
+func fromCache(cfg DownloadConfig) *DownloadResult {
+	// The cache is keyed by SHA-256; other digests are checked after the copy
+	lookupSHA := cfg.ExpectedChecksum
+	if algo, _ := splitChecksum(lookupSHA); algo != "sha256" {
+		lookupSHA = ""
+	}
+	entry, blob, err := cfg.Cache.Lookup(cfg.URL, lookupSHA)
+	if err != nil || entry == nil {
+		return nil
+	}
+	if cfg.ExpectedChecksum == "" && !cfg.Offline && !notModified(cfg.URL, entry) {
+		return nil
+	}
+
+	_ = os.Remove(cfg.DestPath)
+	if err := copyFile(blob, cfg.DestPath); err != nil {
+		return nil
+	}
+	if err := verifyChecksum(cfg.DestPath, entry.SHA256); err != nil {
+		_ = os.Remove(cfg.DestPath)
+		_ = os.Remove(blob)
+		return nil
+	}
+	if cfg.ExpectedChecksum != "" && verifyChecksum(cfg.DestPath, cfg.ExpectedChecksum) != nil {
+		_ = os.Remove(cfg.DestPath)
+		_ = os.Remove(blob)
+		return nil
+	}
+
+	fmt.Printf("Using cached download (%s)\n", FormatBytes(entry.Size))
+	return &DownloadResult{
+		Size:        entry.Size,
+		SHA256:      entry.SHA256,
+		ContentType: entry.ContentType,
+		FromCache:   true,
+	}
+}
+
+// notModified asks the server whether the download cached in entry is still
+// what url serves. Entries without an ETag or Last-Modified cannot be
+// checked and count as changed. A HEAD request keeps a changed file from
+// being transferred twice.
+func notModified(url string, entry *CacheEntry) bool {
+	if entry.ETag == "" && entry.LastModified == "" {
+		return false
+	}
+	req, err := http.NewRequest("HEAD", url, nil)
+	if err != nil {
+		return false
+	}
+	if entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode == http.StatusNotModified
+}
+
 // downloadOnce performs a single download attempt
 func downloadOnce(cfg DownloadConfig) (*DownloadResult, error) {
 	result := &DownloadResult{}
@@ -140,6 +237,10 @@ func downloadOnce(cfg DownloadConfig) (*DownloadResult, error) {
 		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
 	}
 
+	result.ContentType = resp.Header.Get("Content-Type")
+	result.ETag = resp.Header.Get("ETag")
+	result.LastModified = resp.Header.Get("Last-Modified")
+
 	// Determine total size
 	totalSize := resp.ContentLength
 	if totalSize > 0 && existingSize > 0 {
@@ -205,31 +306,53 @@ func isNonRetryableError(err error) bool {
 
 // calculateSHA256 calculates the SHA256 hash of a file
 func calculateSHA256(filePath string) (string, error) {
+	return calculateDigest(filePath, sha256.New())
+}
+
+// calculateDigest hashes a file with the given hash function
+func calculateDigest(filePath string, h hash.Hash) (string, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return "", err
 	}
 	defer func() { _ = file.Close() }()
 
-	hash := sha256.New()
-	if _, err := io.Copy(hash, file); err != nil {
+	if _, err := io.Copy(h, file); err != nil {
 		return "", err
 	}
 
-	return hex.EncodeToString(hash.Sum(nil)), nil
+	return hex.EncodeToString(h.Sum(nil)), nil
 }
 
-// verifyChecksum verifies the SHA256 checksum of a file
+// splitChecksum splits an expected checksum into its algorithm and digest.
+// Unprefixed checksums are SHA256; "sha1:<hex>" selects SHA-1.
+func splitChecksum(expected string) (algo, digest string) {
+	expected = strings.ToLower(strings.TrimSpace(expected))
+	if algo, digest, ok := strings.Cut(expected, ":"); ok {
+		return algo, digest
+	}
+	return "sha256", expected
+}
+
+// verifyChecksum verifies the checksum of a file, SHA256 unless expected
+// carries an algorithm prefix
 func verifyChecksum(filePath, expected string) error {
-	actual, err := calculateSHA256(filePath)
+	algo, expected := splitChecksum(expected)
+
+	var actual string
+	var err error
+	switch algo {
+	case "sha256":
+		actual, err = calculateSHA256(filePath)
+	case "sha1":
+		actual, err = calculateDigest(filePath, sha1.New())
+	default:
+		return fmt.Errorf("unsupported checksum algorithm: %s", algo)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to calculate checksum: %w", err)
 	}
 
-	// Normalize both to lowercase for comparison
-	expected = strings.ToLower(strings.TrimSpace(expected))
-	actual = strings.ToLower(actual)
-
 	if actual != expected {
 		return fmt.Errorf("checksum mismatch: expected %s, got %s", expected, actual)
 	}
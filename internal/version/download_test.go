@@ -1,14 +1,18 @@
 package version
 
 import (
+	"crypto/sha1"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/azdren/verman/internal/sources"
 )
 
 func TestDownloadWithRetry_Success(t *testing.T) {
@@ -68,7 +72,7 @@ func TestDownloadWithRetry_WithChecksum(t *testing.T) {
 	cfg := DefaultDownloadConfig()
 	cfg.URL = server.URL
 	cfg.DestPath = destPath
-	cfg.ExpectedSHA256 = expectedChecksum
+	cfg.ExpectedChecksum = expectedChecksum
 
 	result, err := DownloadWithRetry(cfg)
 	if err != nil {
@@ -94,7 +98,7 @@ func TestDownloadWithRetry_ChecksumMismatch(t *testing.T) {
 	cfg := DefaultDownloadConfig()
 	cfg.URL = server.URL
 	cfg.DestPath = destPath
-	cfg.ExpectedSHA256 = "0000000000000000000000000000000000000000000000000000000000000000"
+	cfg.ExpectedChecksum = "0000000000000000000000000000000000000000000000000000000000000000"
 	cfg.MaxRetries = 1 // Reduce retries for faster test
 
 	_, err := DownloadWithRetry(cfg)
@@ -201,6 +205,15 @@ func TestVerifyChecksum(t *testing.T) {
 	if err := verifyChecksum(filePath, "badchecksum"); err == nil {
 		t.Error("verifyChecksum should fail for incorrect checksum")
 	}
+
+	// Maven repositories often publish only SHA-1
+	sha1Sum := sha1.Sum(content)
+	if err := verifyChecksum(filePath, "sha1:"+hex.EncodeToString(sha1Sum[:])); err != nil {
+		t.Errorf("verifyChecksum failed for correct SHA-1 checksum: %v", err)
+	}
+	if err := verifyChecksum(filePath, "sha1:"+correctChecksum); err == nil {
+		t.Error("verifyChecksum should fail for a SHA-256 digest labelled sha1")
+	}
 }
 
 func TestIsHexString(t *testing.T) {
@@ -225,3 +238,26 @@ func TestIsHexString(t *testing.T) {
 		}
 	}
 }
+
+func TestDownloadWithRetry_OfflineMiss(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	cfg := DefaultDownloadConfig()
+	cfg.URL = server.URL + "/jdk.zip"
+	cfg.DestPath = filepath.Join(tmpDir, "jdk.zip")
+	cfg.Cache = NewCache(filepath.Join(tmpDir, "cache"), 0)
+	cfg.Offline = true
+
+	_, err := DownloadWithRetry(cfg)
+	if !errors.Is(err, sources.ErrNotCached) {
+		t.Errorf("Expected ErrNotCached, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Offline download must not hit the network, got %d requests", requests)
+	}
+}
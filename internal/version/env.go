@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/azdren/verman/internal/config"
@@ -16,142 +17,191 @@ type EnvExport struct {
 	Value string
 }
 
-// GetEnvExports returns environment exports for shell integration
-func GetEnvExports(cfg *config.Config, langName string) ([]EnvExport, error) {
-	lang, ok := languages.Get(langName)
-	if !ok {
-		return nil, fmt.Errorf("unknown language: %s", langName)
-	}
-
-	currentPath := cfg.GetCurrentPath(langName)
-	if _, err := os.Stat(currentPath); os.IsNotExist(err) {
-		return nil, nil // No version set
-	}
-
-	var exports []EnvExport
+// LanguageEnv is the environment one version of a language contributes:
+// its variables (JAVA_HOME, ...) and the directories it puts on PATH. The
+// init generators and the env, shell, hook and exec commands all build on it.
+type LanguageEnv struct {
+	Language string
+	Vars     []EnvExport // Sorted by name
+	Path     []string
+}
 
-	// Add HOME-style variables
+// NewLanguageEnv returns the environment of a language version at root
+// (a version folder or the "current" link)
+func NewLanguageEnv(lang languages.Language, root string) LanguageEnv {
+	env := LanguageEnv{Language: lang.Name()}
 	for envVar, relPath := range lang.EnvVars() {
-		fullPath := currentPath
-		if relPath != "." {
-			fullPath = filepath.Join(currentPath, relPath)
-		}
-		exports = append(exports, EnvExport{Name: envVar, Value: fullPath})
+		env.Vars = append(env.Vars, EnvExport{Name: envVar, Value: joinRel(root, relPath)})
 	}
-
-	return exports, nil
+	sort.Slice(env.Vars, func(i, j int) bool { return env.Vars[i].Name < env.Vars[j].Name })
+	for _, dir := range lang.PathDirs() {
+		env.Path = append(env.Path, joinRel(root, dir))
+	}
+	return env
 }
 
-// GetPathAdditions returns PATH additions for a language
-func GetPathAdditions(cfg *config.Config, langName string) ([]string, error) {
-	lang, ok := languages.Get(langName)
-	if !ok {
-		return nil, fmt.Errorf("unknown language: %s", langName)
+// GlobalEnv returns the environment of every language with a global
+// version, rooted at its "current" link so it follows "verman use"
+func GlobalEnv(cfg *config.Config) []LanguageEnv {
+	var envs []LanguageEnv
+	for _, lang := range languages.All() {
+		currentPath := cfg.GetCurrentPath(lang.Name())
+		if _, err := os.Stat(currentPath); os.IsNotExist(err) {
+			continue
+		}
+		envs = append(envs, NewLanguageEnv(lang, currentPath))
 	}
+	return envs
+}
 
-	currentPath := cfg.GetCurrentPath(langName)
-	if _, err := os.Stat(currentPath); os.IsNotExist(err) {
-		return nil, nil
+// SelectedEnv returns the environment of the versions in effect in dir: the
+// session, project or global version of each language (see Select).
+// Versions set but not installed are reported as errors and skipped.
+func (m *Manager) SelectedEnv(dir string) ([]LanguageEnv, []error) {
+	var envs []LanguageEnv
+	var errs []error
+	for _, lang := range languages.All() {
+		sel, err := m.Select(lang.Name(), dir)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if sel != nil {
+			envs = append(envs, NewLanguageEnv(lang, sel.Path))
+		}
 	}
+	return envs, errs
+}
 
-	var paths []string
-	for _, dir := range lang.PathDirs() {
-		if dir == "." {
-			paths = append(paths, currentPath)
-		} else {
-			paths = append(paths, filepath.Join(currentPath, dir))
+// HookEnv returns the variables (JAVA_HOME, ...) of the versions in effect
+// in dir, for the shell hook. PATH is left alone, as the shims already
+// follow the directory.
+func (m *Manager) HookEnv(dir string) ([]EnvExport, []error) {
+	var exports []EnvExport
+	var errs []error
+	for _, lang := range languages.All() {
+		if len(lang.EnvVars()) == 0 {
+			continue // Only variables matter here, so skip checking the version
+		}
+		sel, err := m.Select(lang.Name(), dir)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if sel != nil {
+			exports = append(exports, NewLanguageEnv(lang, sel.Path).Vars...)
 		}
 	}
-
-	return paths, nil
+	sort.SliceStable(exports, func(i, j int) bool { return exports[i].Name < exports[j].Name })
+	return dedupeExports(exports), errs
 }
 
-// GeneratePowerShellInit generates PowerShell script for shell integration
-func GeneratePowerShellInit(cfg *config.Config) string {
-	var sb strings.Builder
+// DirEnv returns the exports that set up a shell for the versions in effect
+// in dir: their variables, and PATH with the shim directory and their
+// directories first. Entries a previous run added are replaced rather than
+// repeated. environ is the shell's current environment (os.Environ()).
+func (m *Manager) DirEnv(dir string, environ []string) ([]EnvExport, []error) {
+	envs, errs := m.SelectedEnv(dir)
 
-	sb.WriteString("# Verman PowerShell Integration\n")
-	sb.WriteString("# Add this to your PowerShell profile ($PROFILE)\n\n")
+	var exports []EnvExport
+	var pathDirs, langNames []string
+	if shimDir, err := ShimDir(); err == nil {
+		pathDirs = append(pathDirs, shimDir)
+	}
+	for _, env := range envs {
+		exports = append(exports, env.Vars...)
+		pathDirs = append(pathDirs, env.Path...)
+		langNames = append(langNames, env.Language)
+	}
+	sort.SliceStable(exports, func(i, j int) bool { return exports[i].Name < exports[j].Name })
+	exports = dedupeExports(exports)
+
+	path := m.prependPath(getEnv(environ, "PATH"), pathDirs, langNames...)
+	return append(exports, EnvExport{Name: "PATH", Value: path}), errs
+}
 
-	// Add all language paths and env vars
+// UnsetEnv returns the exports that undo DirEnv and SessionEnv: the session
+// variables and the language variables that point into a version removed
+// (a JAVA_HOME set to a system JDK stays), and the shim directory and all
+// version directories taken off PATH
+func (m *Manager) UnsetEnv(environ []string) []EnvExport {
+	var exports []EnvExport
+	var langNames []string
 	for _, lang := range languages.All() {
-		currentPath := cfg.GetCurrentPath(lang.Name())
-		if _, err := os.Stat(currentPath); os.IsNotExist(err) {
-			continue
+		langNames = append(langNames, lang.Name())
+	}
+	for _, lang := range languages.All() {
+		if getEnv(environ, SessionVar(lang.Name())) != "" {
+			exports = append(exports, EnvExport{Name: SessionVar(lang.Name())})
 		}
-
-		sb.WriteString(fmt.Sprintf("# %s\n", lang.Name()))
-
-		for envVar, relPath := range lang.EnvVars() {
-			fullPath := currentPath
-			if relPath != "." {
-				fullPath = filepath.Join(currentPath, relPath)
+		for envVar := range lang.EnvVars() {
+			if value := getEnv(environ, envVar); value != "" && m.isLanguageDir(value, langNames) {
+				exports = append(exports, EnvExport{Name: envVar})
 			}
-			sb.WriteString(fmt.Sprintf("$env:%s = '%s'\n", envVar, fullPath))
 		}
-
-		for _, dir := range lang.PathDirs() {
-			pathDir := currentPath
-			if dir != "." {
-				pathDir = filepath.Join(currentPath, dir)
+	}
+	sort.Slice(exports, func(i, j int) bool { return exports[i].Name < exports[j].Name })
+	exports = dedupeExports(exports)
+
+	path := m.prependPath(getEnv(environ, "PATH"), nil, langNames...)
+	if shimDir, err := ShimDir(); err == nil {
+		var rest []string
+		for _, dir := range filepath.SplitList(path) {
+			if pathKey(dir) != pathKey(shimDir) {
+				rest = append(rest, dir)
 			}
-			sb.WriteString(fmt.Sprintf("$env:PATH = '%s;' + $env:PATH\n", pathDir))
 		}
-		sb.WriteString("\n")
+		path = strings.Join(rest, string(os.PathListSeparator))
 	}
-
-	// Add auto-detect hook
-	sb.WriteString(`# Auto-detect versions on directory change
-function Set-VermanVersions {
-    $detected = & verman detect --quiet --json 2>$null | ConvertFrom-Json
-    if ($detected) {
-        foreach ($item in $detected) {
-            & verman use $item.language $item.version --quiet 2>$null
-        }
-    }
-}
-
-# Hook into directory change (optional - uncomment to enable)
-# $ExecutionContext.SessionState.InvokeCommand.PreCommandLookupAction = {
-#     param($CommandName, $CommandLookupEventArgs)
-#     if ($CommandName -eq 'cd' -or $CommandName -eq 'Set-Location') {
-#         Set-VermanVersions
-#     }
-# }
-`)
-
-	return sb.String()
+	return append(exports, EnvExport{Name: "PATH", Value: path})
 }
 
-// GenerateCmdInit generates batch script for CMD integration
-func GenerateCmdInit(cfg *config.Config) string {
-	var sb strings.Builder
-
-	sb.WriteString("@echo off\n")
-	sb.WriteString("REM Verman CMD Integration\n\n")
-
-	for _, lang := range languages.All() {
-		currentPath := cfg.GetCurrentPath(lang.Name())
-		if _, err := os.Stat(currentPath); os.IsNotExist(err) {
+// dedupeExports drops repeated names from sorted exports, keeping the first
+func dedupeExports(exports []EnvExport) []EnvExport {
+	var out []EnvExport
+	for _, e := range exports {
+		if len(out) > 0 && out[len(out)-1].Name == e.Name {
 			continue
 		}
+		out = append(out, e)
+	}
+	return out
+}
 
-		for envVar, relPath := range lang.EnvVars() {
-			fullPath := currentPath
-			if relPath != "." {
-				fullPath = filepath.Join(currentPath, relPath)
-			}
-			sb.WriteString(fmt.Sprintf("SET %s=%s\n", envVar, fullPath))
+// SessionEnv returns the exports that switch the calling shell to an
+// installed version of a language: the session variable, the language's
+// environment variables and PATH with its bin directories first. An empty
+// version clears the session override and restores the global version.
+// environ is the shell's current environment (os.Environ()).
+func (m *Manager) SessionEnv(langName, version string, environ []string) ([]EnvExport, error) {
+	lang, ok := languages.Get(langName)
+	if !ok {
+		return nil, fmt.Errorf("unknown language: %s", langName)
+	}
+
+	root := m.Config.GetCurrentPath(langName)
+	if version != "" {
+		resolved, err := m.resolveExecVersion(langName, version)
+		if err != nil {
+			return nil, err
 		}
+		version = resolved
+		root = m.Config.GetVersionPath(langName, resolved)
+	} else if _, err := os.Stat(root); err != nil {
+		root = "" // No global version to fall back to
+	}
 
-		for _, dir := range lang.PathDirs() {
-			pathDir := currentPath
-			if dir != "." {
-				pathDir = filepath.Join(currentPath, dir)
-			}
-			sb.WriteString(fmt.Sprintf("SET PATH=%s;%%PATH%%\n", pathDir))
+	exports := []EnvExport{{Name: SessionVar(langName), Value: version}}
+	env := NewLanguageEnv(lang, root)
+	if root == "" {
+		for _, v := range env.Vars {
+			exports = append(exports, EnvExport{Name: v.Name}) // Unset
 		}
+		env.Path = nil
+	} else {
+		exports = append(exports, env.Vars...)
 	}
 
-	return sb.String()
+	path := m.prependPath(getEnv(environ, "PATH"), env.Path, langName)
+	return append(exports, EnvExport{Name: "PATH", Value: path}), nil
 }
@@ -5,59 +5,126 @@ package version
 import (
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 )
 
-// setUserEnvVar sets a user environment variable persistently on Unix-like systems
+// setUserEnvVar sets a user environment variable persistently on Unix-like
+// systems: as a fish universal variable (set -Ux) for fish, otherwise in
+// the managed block of the shell's profile, rewritten in place
 func setUserEnvVar(name, value string) error {
-	// On Unix, we append to shell profile
-	home, err := os.UserHomeDir()
+	if DetectShell() == ShellFish {
+		return runFish(fmt.Sprintf("set -Ux %s %s", name, ShellFish.Quote(value)))
+	}
+
+	profilePath, err := userProfile()
 	if err != nil {
 		return err
 	}
+	return setProfileVar(profilePath, name, value)
+}
 
-	// Try to detect the shell and update appropriate profile
-	shell := os.Getenv("SHELL")
-	var profilePath string
-
-	switch {
-	case contains(shell, "zsh"):
-		profilePath = filepath.Join(home, ".zshrc")
-	case contains(shell, "bash"):
-		profilePath = filepath.Join(home, ".bashrc")
-	default:
-		profilePath = filepath.Join(home, ".profile")
+// getUserEnvVar gets a variable set by setUserEnvVar
+func getUserEnvVar(name string) (string, error) {
+	if DetectShell() == ShellFish {
+		out, err := exec.Command("fish", "-c", fmt.Sprintf("set -qU %s; and echo $%s", name, name)).Output()
+		return strings.TrimSpace(string(out)), err
 	}
 
-	// Append export statement
-	f, err := os.OpenFile(profilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	profilePath, err := userProfile()
 	if err != nil {
-		return err
+		return "", err
 	}
-	defer f.Close()
+	content, err := os.ReadFile(profilePath)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	return blockVar(profileBlock(string(content)), name), nil
+}
 
-	line := fmt.Sprintf("\nexport %s=\"%s\"\n", name, value)
-	if _, err := f.WriteString(line); err != nil {
-		return err
+// removeUserEnv removes what setUserEnvVar persisted: the managed block from
+// every profile it may have gone to, and those of the named fish universal
+// variables that point under root. It returns where it removed them from.
+func removeUserEnv(names []string, root string) ([]string, error) {
+	var removed []string
+	profiles, err := userProfiles()
+	if err != nil {
+		return nil, err
+	}
+	for _, profilePath := range profiles {
+		ok, err := removeProfileBlock(profilePath)
+		if err != nil {
+			return removed, err
+		}
+		if ok {
+			removed = append(removed, profilePath)
+		}
 	}
 
-	return nil
+	if _, err := exec.LookPath("fish"); err != nil || len(names) == 0 {
+		return removed, nil
+	}
+	prefix := ShellFish.Quote(filepath.Clean(root) + string(filepath.Separator) + "*")
+	var script []string
+	for _, name := range names {
+		script = append(script, fmt.Sprintf("set -qU %s; and string match -q -- %s $%s; and set -eU %s; and echo %s",
+			name, prefix, name, name, name))
+	}
+	script = append(script, "true") // A failed match is not an error
+	out, err := exec.Command("fish", "-c", strings.Join(script, "; ")).Output()
+	if err != nil {
+		return removed, fmt.Errorf("fish failed: %w", err)
+	}
+	for _, name := range strings.Fields(string(out)) {
+		removed = append(removed, "fish universal variable "+name)
+	}
+	return removed, nil
 }
 
-// getUserEnvVar gets an environment variable (just uses os.Getenv on Unix)
-func getUserEnvVar(name string) (string, error) {
-	return os.Getenv(name), nil
+// userProfile returns the profile of the user's shell that setUserEnvVar
+// writes to: .zshrc for zsh, .bashrc for bash and .profile otherwise
+func userProfile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	switch DetectShell() {
+	case ShellZsh:
+		if dir := os.Getenv("ZDOTDIR"); dir != "" {
+			return filepath.Join(dir, ".zshrc"), nil
+		}
+		return filepath.Join(home, ".zshrc"), nil
+	case ShellBash:
+		if filepath.Base(os.Getenv("SHELL")) == "bash" {
+			return filepath.Join(home, ".bashrc"), nil
+		}
+	}
+	return filepath.Join(home, ".profile"), nil
 }
 
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && (s == substr || len(s) > 0 && containsSubstr(s, substr))
+// userProfiles returns every profile userProfile may pick
+func userProfiles() ([]string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	profiles := []string{
+		filepath.Join(home, ".bashrc"),
+		filepath.Join(home, ".zshrc"),
+		filepath.Join(home, ".profile"),
+	}
+	if dir := os.Getenv("ZDOTDIR"); dir != "" && filepath.Clean(dir) != filepath.Clean(home) {
+		profiles = append(profiles, filepath.Join(dir, ".zshrc"))
+	}
+	return profiles, nil
 }
 
-func containsSubstr(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
-		}
+// runFish runs a fish command, reporting its output on failure
+func runFish(command string) error {
+	out, err := exec.Command("fish", "-c", command).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("fish failed: %w (output: %s)", err, strings.TrimSpace(string(out)))
 	}
-	return false
+	return nil
 }
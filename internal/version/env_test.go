@@ -0,0 +1,173 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateInit(t *testing.T) {
+	mgr, home := setupTestManager(t)
+	java17 := createMockVersion(t, mgr, "java", "17.0.9")
+	if err := switchJunction(mgr.Config.GetCurrentPath("java"), java17); err != nil {
+		t.Fatalf("switchJunction failed: %v", err)
+	}
+	shimDir := filepath.Join(home, ".verman", "bin")
+	javaHome := mgr.Config.GetCurrentPath("java")
+
+	generate := func(sh Shell, hook bool) string {
+		t.Helper()
+		script, err := GenerateInit(mgr.Config, sh, "/opt/verman", hook)
+		if err != nil {
+			t.Fatalf("GenerateInit(%s) failed: %v", sh, err)
+		}
+		return script
+	}
+
+	bash := generate(ShellBash, false)
+	for _, want := range []string{
+		"export PATH='" + shimDir + "':\"$PATH\"",
+		"export JAVA_HOME='" + javaHome + "'",
+		"eval \"$('/opt/verman' completion bash)\"",
+	} {
+		if !strings.Contains(bash, want) {
+			t.Errorf("bash init is missing %q:\n%s", want, bash)
+		}
+	}
+	if strings.Contains(bash, "_verman_hook()") {
+		t.Error("bash init should not install the hook unless asked")
+	}
+
+	zsh := generate(ShellZsh, true)
+	if !strings.Contains(zsh, "add-zsh-hook chpwd _verman_hook") || !strings.Contains(zsh, "hook zsh") {
+		t.Errorf("zsh init is missing the chpwd hook:\n%s", zsh)
+	}
+
+	fish := generate(ShellFish, true)
+	for _, want := range []string{
+		"set -gx PATH '" + shimDir + "' $PATH",
+		"set -gx JAVA_HOME '" + javaHome + "'",
+		"function _verman_hook --on-variable PWD",
+	} {
+		if !strings.Contains(fish, want) {
+			t.Errorf("fish init is missing %q:\n%s", want, fish)
+		}
+	}
+
+	nu := generate(ShellNu, true)
+	for _, want := range []string{
+		"path add \"" + shimDir + "\"",
+		"load-env {JAVA_HOME: \"" + javaHome + "\"}",
+		"^\"/opt/verman\" hook nu | from nuon | load-env",
+	} {
+		if !strings.Contains(nu, want) {
+			t.Errorf("nu init is missing %q:\n%s", want, nu)
+		}
+	}
+
+	elvish := generate(ShellElvish, false)
+	if !strings.Contains(elvish, "set-env JAVA_HOME '"+javaHome+"'") {
+		t.Errorf("elvish init is missing JAVA_HOME:\n%s", elvish)
+	}
+
+	// Windows shells put each version's directories on PATH
+	pwsh := generate(ShellPwsh, false)
+	if !strings.Contains(pwsh, "# Verman PowerShell Integration") || !strings.Contains(pwsh, filepath.Join(javaHome, "bin")) {
+		t.Errorf("PowerShell init is missing Java's bin directory:\n%s", pwsh)
+	}
+	if _, err := GenerateInit(mgr.Config, ShellCmd, "/opt/verman", true); err == nil {
+		t.Error("Expected error for --hook with cmd")
+	}
+}
+
+func TestDirEnv(t *testing.T) {
+	mgr, home := setupTestManager(t)
+	java17 := createMockVersion(t, mgr, "java", "17.0.9")
+	java21 := createMockVersion(t, mgr, "java", "21.0.2")
+	t.Setenv(SessionVar("java"), "")
+
+	project := filepath.Join(home, "project")
+	_ = os.MkdirAll(project, 0755)
+	_ = os.WriteFile(filepath.Join(project, ".java-version"), []byte("17\n"), 0644)
+
+	sep := string(os.PathListSeparator)
+	shimDir := filepath.Join(home, ".verman", "bin")
+	environ := []string{"PATH=" + shimDir + sep + filepath.Join(java21, "bin") + sep + "/usr/bin"}
+
+	exports, errs := mgr.DirEnv(project, environ)
+	if len(errs) != 0 {
+		t.Fatalf("DirEnv errors: %v", errs)
+	}
+	got := make(map[string]string)
+	for _, e := range exports {
+		got[e.Name] = e.Value
+	}
+	if got["JAVA_HOME"] != java17 {
+		t.Errorf("JAVA_HOME = %s, want %s", got["JAVA_HOME"], java17)
+	}
+	// Running it again must not repeat entries or keep the old version
+	wantPath := shimDir + sep + filepath.Join(java17, "bin") + sep + "/usr/bin"
+	if got["PATH"] != wantPath {
+		t.Errorf("PATH = %s, want %s", got["PATH"], wantPath)
+	}
+}
+
+func TestHookEnv(t *testing.T) {
+	mgr, tmpDir := setupTestManager(t)
+	java17 := createMockVersion(t, mgr, "java", "17.0.9")
+	t.Setenv(SessionVar("java"), "")
+
+	project := filepath.Join(tmpDir, "project")
+	_ = os.MkdirAll(project, 0755)
+	_ = os.WriteFile(filepath.Join(project, ".java-version"), []byte("17\n"), 0644)
+	_ = os.WriteFile(filepath.Join(project, ".nvmrc"), []byte("20\n"), 0644)
+
+	exports, errs := mgr.HookEnv(project)
+	if len(exports) != 1 || exports[0].Name != "JAVA_HOME" || exports[0].Value != java17 {
+		t.Errorf("HookEnv exports = %+v, want JAVA_HOME=%s", exports, java17)
+	}
+	// Node has no environment variables, so its missing version is not checked
+	if len(errs) != 0 {
+		t.Errorf("HookEnv errors = %v", errs)
+	}
+
+	_ = os.WriteFile(filepath.Join(project, ".java-version"), []byte("11\n"), 0644)
+	if exports, errs := mgr.HookEnv(project); len(exports) != 0 || len(errs) != 1 {
+		t.Errorf("HookEnv with Java 11 missing = %+v, %v; want one error", exports, errs)
+	}
+}
+
+func TestUnsetEnv(t *testing.T) {
+	mgr, home := setupTestManager(t)
+	java17 := createMockVersion(t, mgr, "java", "17.0.9")
+
+	sep := string(os.PathListSeparator)
+	shimDir := filepath.Join(home, ".verman", "bin")
+	environ := []string{
+		"PATH=" + shimDir + sep + filepath.Join(java17, "bin") + sep + "/usr/bin" + sep + "/usr/bin/",
+		"JAVA_HOME=" + java17,
+		"GOROOT=/usr/lib/go", // Not verman's, so left alone
+		SessionVar("java") + "=17.0.9",
+	}
+
+	got := make(map[string]string)
+	for _, e := range mgr.UnsetEnv(environ) {
+		if _, dup := got[e.Name]; dup {
+			t.Errorf("%s is listed twice", e.Name)
+		}
+		got[e.Name] = e.Value
+	}
+	if v, ok := got["JAVA_HOME"]; !ok || v != "" {
+		t.Errorf("JAVA_HOME should be removed, got %q", v)
+	}
+	if v, ok := got[SessionVar("java")]; !ok || v != "" {
+		t.Errorf("%s should be removed, got %q", SessionVar("java"), v)
+	}
+	if _, ok := got["GOROOT"]; ok {
+		t.Error("GOROOT outside the versions root should be left alone")
+	}
+	if got["PATH"] != "/usr/bin" {
+		t.Errorf("PATH = %q, want /usr/bin", got["PATH"])
+	}
+}
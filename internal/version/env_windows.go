@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"unsafe"
 
@@ -214,3 +215,30 @@ func getUserEnvVar(name string) (string, error) {
 	}
 	return value, nil
 }
+
+// removeUserEnv removes the named user environment variables that point
+// under root from the registry, and returns the ones it removed
+func removeUserEnv(names []string, root string) ([]string, error) {
+	key, err := registry.OpenKey(registry.CURRENT_USER, `Environment`, registry.QUERY_VALUE|registry.SET_VALUE)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open registry key: %w", err)
+	}
+	defer func() { _ = key.Close() }()
+
+	var removed []string
+	prefix := pathKey(root) + string(filepath.Separator)
+	for _, name := range names {
+		value, _, err := key.GetStringValue(name)
+		if err != nil || !strings.HasPrefix(pathKey(value), prefix) {
+			continue
+		}
+		if err := key.DeleteValue(name); err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %w", name, err)
+		}
+		removed = append(removed, "user environment variable "+name)
+	}
+	if len(removed) > 0 {
+		broadcastSettingChange()
+	}
+	return removed, nil
+}
@@ -0,0 +1,199 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/azdren/verman/internal/languages"
+	"github.com/azdren/verman/internal/sources"
+)
+
+// ToolVersion names an installed version to run a command with
+type ToolVersion struct {
+	Language string
+	Version  string
+}
+
+// ParseToolVersion parses "java@17" or "maven@3.9"
+func ParseToolVersion(spec string) (ToolVersion, error) {
+	lang, ver, ok := strings.Cut(spec, "@")
+	if !ok || lang == "" || ver == "" {
+		return ToolVersion{}, fmt.Errorf("invalid tool %q: use <language>@<version>, e.g. java@17", spec)
+	}
+	return ToolVersion{Language: lang, Version: ver}, nil
+}
+
+// ExecEnv returns base (usually os.Environ()) adjusted to run a command with
+// the given installed versions: their environment variables set and their
+// PATH directories first. Dependencies that are not named (Java for Maven)
+// use their current version. Nothing on disk changes, so the global
+// "current" version and config.json are left alone.
+func (m *Manager) ExecEnv(tools []ToolVersion, base []string) ([]string, error) {
+	env := append([]string(nil), base...)
+	var pathDirs []string
+	named := make(map[string]bool)
+
+	add := func(lang languages.Language, root string) {
+		langEnv := NewLanguageEnv(lang, root)
+		for _, v := range langEnv.Vars {
+			env = setEnv(env, v.Name, v.Value)
+		}
+		pathDirs = append(pathDirs, langEnv.Path...)
+	}
+
+	for _, tool := range tools {
+		lang, ok := languages.Get(tool.Language)
+		if !ok {
+			return nil, fmt.Errorf("unknown language: %s", tool.Language)
+		}
+		if named[tool.Language] {
+			return nil, fmt.Errorf("%s is named more than once", tool.Language)
+		}
+		named[tool.Language] = true
+
+		resolved, err := m.resolveExecVersion(tool.Language, tool.Version)
+		if err != nil {
+			return nil, err
+		}
+		add(lang, m.Config.GetVersionPath(tool.Language, resolved))
+	}
+
+	for _, tool := range tools {
+		lang, _ := languages.Get(tool.Language)
+		for _, dep := range lang.GetDependencies() {
+			if named[dep] {
+				continue
+			}
+			named[dep] = true
+			depLang, ok := languages.Get(dep)
+			currentPath := m.Config.GetCurrentPath(dep)
+			if _, err := os.Stat(currentPath); !ok || err != nil {
+				continue // Missing dependencies are reported by the tool itself
+			}
+			add(depLang, currentPath)
+		}
+	}
+
+	// Drop the named languages' other directories (current, or a session
+	// version) from PATH so nothing falls through to the switched-in version
+	var langNames []string
+	for _, tool := range tools {
+		langNames = append(langNames, tool.Language)
+	}
+	env = setEnv(env, "PATH", m.prependPath(getEnv(env, "PATH"), pathDirs, langNames...))
+	return env, nil
+}
+
+// resolveExecVersion finds the installed version to run: an exact folder
+// name, an alias or range, or the newest installed build of a partial
+// version ("17" -> "17.0.9")
+func (m *Manager) resolveExecVersion(langName, version string) (string, error) {
+	resolved, err := m.ResolveInstalled(langName, version)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(m.Config.GetVersionPath(langName, resolved)); err == nil {
+		return resolved, nil
+	}
+
+	base, dist := sources.ParseVersionAndDistribution(resolved)
+	spec := "=" + base
+	if dist != "" {
+		spec += "-" + dist
+	}
+	if partial, err := m.ResolveInstalled(langName, spec); err == nil {
+		return partial, nil
+	}
+	return "", fmt.Errorf("version %s not installed for %s", version, langName)
+}
+
+// prependPath puts dirs at the front of a PATH value after removing every
+// entry that lies inside the given languages' version folders, and any
+// duplicates
+func (m *Manager) prependPath(path string, dirs []string, langNames ...string) string {
+	var result []string
+	seen := make(map[string]bool)
+	add := func(dir string) {
+		if key := pathKey(dir); dir != "" && !seen[key] {
+			seen[key] = true
+			result = append(result, dir)
+		}
+	}
+	for _, dir := range dirs {
+		add(dir)
+	}
+	for _, dir := range filepath.SplitList(path) {
+		if !m.isLanguageDir(dir, langNames) {
+			add(dir)
+		}
+	}
+	return strings.Join(result, string(os.PathListSeparator))
+}
+
+// isLanguageDir reports whether a PATH entry lies in an installed or current
+// version of one of the languages
+func (m *Manager) isLanguageDir(dir string, langNames []string) bool {
+	for _, langName := range langNames {
+		root := filepath.Join(m.Config.RootPath, langName)
+		if strings.HasPrefix(pathKey(dir), pathKey(root)+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// lookPath finds a command on the PATH of env rather than verman's own. The
+// process PATH is switched over, as the command is about to replace it.
+func lookPath(file string, env []string) (string, error) {
+	_ = os.Setenv("PATH", getEnv(env, "PATH"))
+	return exec.LookPath(file)
+}
+
+// pathKey identifies a PATH entry for de-duplication: "/usr/bin/" and
+// "/usr/bin" are the same, and so are "C:\\JDK" and "c:\\jdk" on Windows
+func pathKey(dir string) string {
+	dir = filepath.Clean(dir)
+	if runtime.GOOS == "windows" {
+		return strings.ToLower(dir)
+	}
+	return dir
+}
+
+func joinRel(root, rel string) string {
+	if rel == "." {
+		return root
+	}
+	return filepath.Join(root, rel)
+}
+
+// envKeyEqual compares variable names, ignoring case on Windows
+func envKeyEqual(a, b string) bool {
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+func getEnv(env []string, key string) string {
+	for _, kv := range env {
+		if k, v, ok := strings.Cut(kv, "="); ok && envKeyEqual(k, key) {
+			return v
+		}
+	}
+	return ""
+}
+
+// setEnv replaces or adds a variable in a KEY=value list
+func setEnv(env []string, key, value string) []string {
+	for i, kv := range env {
+		if k, _, ok := strings.Cut(kv, "="); ok && envKeyEqual(k, key) {
+			env[i] = key + "=" + value
+			return env
+		}
+	}
+	return append(env, key+"="+value)
+}
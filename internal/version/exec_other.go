@@ -0,0 +1,15 @@
+//go:build !windows
+
+package version
+
+import "syscall"
+
+// RunCommand replaces verman with the command, so its exit code and
+// signals reach the caller directly. It only returns on failure.
+func RunCommand(command, env []string) (int, error) {
+	path, err := lookPath(command[0], env)
+	if err != nil {
+		return 127, err
+	}
+	return 126, syscall.Exec(path, command, env)
+}
@@ -0,0 +1,69 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseToolVersion(t *testing.T) {
+	tool, err := ParseToolVersion("java@17-amzn")
+	if err != nil || tool.Language != "java" || tool.Version != "17-amzn" {
+		t.Errorf("ParseToolVersion(java@17-amzn) = %+v, %v", tool, err)
+	}
+	for _, spec := range []string{"java", "java@", "@17"} {
+		if _, err := ParseToolVersion(spec); err == nil {
+			t.Errorf("Expected error for %q", spec)
+		}
+	}
+}
+
+func TestExecEnv(t *testing.T) {
+	mgr, _ := setupTestManager(t)
+	java17 := createMockVersion(t, mgr, "java", "17.0.9")
+	createMockVersion(t, mgr, "java", "21.0.2")
+	maven := createMockVersion(t, mgr, "maven", "3.9.6")
+
+	sep := string(os.PathListSeparator)
+	javaCurrentBin := filepath.Join(mgr.Config.GetCurrentPath("java"), "bin")
+	base := []string{"HOME=/home/dev", "JAVA_HOME=/opt/ide-jdk", "PATH=" + javaCurrentBin + sep + "/usr/bin"}
+
+	env, err := mgr.ExecEnv([]ToolVersion{{"java", "17"}, {"maven", "3.9"}}, base)
+	if err != nil {
+		t.Fatalf("ExecEnv failed: %v", err)
+	}
+
+	if got := getEnv(env, "JAVA_HOME"); got != java17 {
+		t.Errorf("JAVA_HOME = %s, want %s", got, java17)
+	}
+	if got := getEnv(env, "HOME"); got != "/home/dev" {
+		t.Errorf("Unrelated variables should be kept, HOME = %s", got)
+	}
+
+	path := strings.Split(getEnv(env, "PATH"), sep)
+	want := []string{filepath.Join(java17, "bin"), filepath.Join(maven, "bin"), "/usr/bin"}
+	if strings.Join(path, sep) != strings.Join(want, sep) {
+		t.Errorf("PATH = %v, want %v", path, want)
+	}
+
+	// The caller's environment is not modified
+	if base[1] != "JAVA_HOME=/opt/ide-jdk" {
+		t.Error("ExecEnv modified its base environment")
+	}
+}
+
+func TestExecEnvErrors(t *testing.T) {
+	mgr, _ := setupTestManager(t)
+	createMockVersion(t, mgr, "java", "17.0.9")
+
+	for _, tools := range [][]ToolVersion{
+		{{"java", "11"}},
+		{{"cobol", "1"}},
+		{{"java", "17"}, {"java", "17.0.9"}},
+	} {
+		if _, err := mgr.ExecEnv(tools, nil); err == nil {
+			t.Errorf("Expected error for %+v", tools)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+//go:build windows
+
+package version
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"os/signal"
+)
+
+// RunCommand runs the command and returns its exit code. Ctrl+C reaches the
+// command through the shared console; verman ignores it and waits.
+func RunCommand(command, env []string) (int, error) {
+	path, err := lookPath(command[0], env)
+	if err != nil {
+		return 9009, err // cmd.exe's "not recognized" code
+	}
+
+	cmd := exec.Command(path, command[1:]...)
+	cmd.Env = env
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return 1, err
+	}
+	return 0, nil
+}
@@ -0,0 +1,654 @@
+package version
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ulikunitz/xz"
+)
+
+// Archive types understood by extractArchive
+const (
+	ArchiveZip   = "zip"
+	ArchiveTarGz = "tar.gz"
+	ArchiveTarXz = "tar.xz"
+)
+
+// DetectArchiveType determines the archive format of a downloaded file.
+// It checks the URL suffix first, then the Content-Type header, and finally
+// sniffs the file's magic bytes (needed for API endpoints like Adoptium's
+// that redirect to a binary without a telling URL).
+func DetectArchiveType(url, contentType, filePath string) (string, error) {
+	if t := archiveTypeFromName(url); t != "" {
+		return t, nil
+	}
+	if t := archiveTypeFromContentType(contentType); t != "" {
+		return t, nil
+	}
+	if t := archiveTypeFromMagic(filePath); t != "" {
+		return t, nil
+	}
+	return "", fmt.Errorf("cannot determine archive type of %s", url)
+}
+
+// archiveTypeFromName maps a URL or file name suffix to an archive type
+func archiveTypeFromName(name string) string {
+	// Ignore query strings (e.g., "...?project=jdk")
+	if idx := strings.IndexAny(name, "?#"); idx >= 0 {
+		name = name[:idx]
+	}
+	name = strings.ToLower(name)
+
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		return ArchiveZip
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return ArchiveTarGz
+	case strings.HasSuffix(name, ".tar.xz"), strings.HasSuffix(name, ".txz"):
+		return ArchiveTarXz
+	}
+	return ""
+}
+
+// archiveTypeFromContentType maps an HTTP Content-Type to an archive type
+func archiveTypeFromContentType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+
+	switch mediaType {
+	case "application/zip", "application/x-zip-compressed":
+		return ArchiveZip
+	case "application/gzip", "application/x-gzip", "application/x-gtar", "application/x-tgz":
+		return ArchiveTarGz
+	case "application/x-xz":
+		return ArchiveTarXz
+	}
+	return ""
+}
+
+// archiveTypeFromMagic sniffs the leading bytes of a file
+func archiveTypeFromMagic(filePath string) string {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = f.Close() }()
+
+	header := make([]byte, 6)
+	n, _ := io.ReadFull(f, header)
+	header = header[:n]
+
+	switch {
+	case bytes.HasPrefix(header, []byte("PK\x03\x04")):
+		return ArchiveZip
+	case bytes.HasPrefix(header, []byte{0x1f, 0x8b}):
+		return ArchiveTarGz
+	case bytes.HasPrefix(header, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}):
+		return ArchiveTarXz
+	}
+	return ""
+}
+
+// ExtractLimits bounds what an archive may unpack to (zip-bomb protection)
+type ExtractLimits struct {
+	MaxSize  int64 // Maximum total uncompressed bytes, 0 for no limit
+	MaxFiles int   // Maximum number of entries, 0 for no limit
+}
+
+// DefaultExtractLimits are generous enough for any JDK or SDK distribution
+func DefaultExtractLimits() ExtractLimits {
+	return ExtractLimits{
+		MaxSize:  4 << 30, // 4 GiB
+		MaxFiles: 100000,
+	}
+}
+
+// extractLimits returns the configured extraction limits, falling back to defaults
+func (m *Manager) extractLimits() ExtractLimits {
+	limits := DefaultExtractLimits()
+	if m.Config.MaxExtractSize > 0 {
+		limits.MaxSize = m.Config.MaxExtractSize
+	}
+	if m.Config.MaxExtractFiles > 0 {
+		limits.MaxFiles = m.Config.MaxExtractFiles
+	}
+	return limits
+}
+
+// UnsafeArchiveError reports an archive that was refused during extraction,
+// e.g. because an entry would escape the destination directory
+type UnsafeArchiveError struct {
+	Entry  string // Archive entry name, empty for archive-wide limits
+	Reason string
+}
+
+func (e *UnsafeArchiveError) Error() string {
+	if e.Entry == "" {
+		return fmt.Sprintf("unsafe archive: %s", e.Reason)
+	}
+	return fmt.Sprintf("unsafe archive entry %q: %s", e.Entry, e.Reason)
+}
+
+// extractArchive extracts an archive of the given type into destPath,
+// stripping a single top-level folder if every entry shares one
+func extractArchive(archivePath, destPath, archiveType string, limits ExtractLimits) error {
+	switch archiveType {
+	case ArchiveZip:
+		return extractZip(archivePath, destPath, limits)
+	case ArchiveTarGz, ArchiveTarXz:
+		return extractTar(archivePath, destPath, archiveType, limits)
+	default:
+		return fmt.Errorf("unsupported archive type: %s", archiveType)
+	}
+}
+
+// commonRootPrefix returns the single top-level folder ("name/") shared by
+// all entries, or "" if the entries do not share one
+func commonRootPrefix(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+
+	// Directory entries may be listed with or without a trailing slash
+	root := names[0]
+	if idx := strings.Index(root, "/"); idx >= 0 {
+		root = root[:idx]
+	}
+	if root == "" {
+		return ""
+	}
+
+	prefix := root + "/"
+	nested := false
+	for _, name := range names {
+		if name == root || name == prefix {
+			continue
+		}
+		if !strings.HasPrefix(name, prefix) {
+			return ""
+		}
+		nested = true
+	}
+
+	// A lone top-level file is not a folder to strip
+	if !nested {
+		return ""
+	}
+	return prefix
+}
+
+// stripRoot removes the common root folder from an entry name. The root
+// folder's own entry maps to "".
+func stripRoot(name, prefix string) string {
+	if prefix == "" {
+		return name
+	}
+	if name+"/" == prefix {
+		return ""
+	}
+	return strings.TrimPrefix(name, prefix)
+}
+
+// pendingLink is a symlink or hard link created after all regular files,
+// so no file is ever written through a link from the same archive
+type pendingLink struct {
+	entry  string
+	path   string
+	target string
+	hard   bool
+}
+
+// extractor writes archive entries under dest while enforcing path safety and limits
+type extractor struct {
+	dest    string
+	limits  ExtractLimits
+	written int64
+	files   int
+	links   []pendingLink
+}
+
+func newExtractor(dest string, limits ExtractLimits) (*extractor, error) {
+	abs, err := filepath.Abs(dest)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(abs, 0755); err != nil {
+		return nil, err
+	}
+	return &extractor{dest: abs, limits: limits}, nil
+}
+
+// entryPath validates an archive entry name and returns its destination path.
+// Absolute paths, drive letters and ".." components are refused.
+func (x *extractor) entryPath(name string) (string, error) {
+	clean, err := cleanEntryName(name)
+	if err != nil {
+		return "", &UnsafeArchiveError{Entry: name, Reason: err.Error()}
+	}
+	return filepath.Join(x.dest, filepath.FromSlash(clean)), nil
+}
+
+// cleanEntryName normalizes an entry name to a relative slash path
+func cleanEntryName(name string) (string, error) {
+	// Archives built on Windows may use backslashes
+	name = strings.ReplaceAll(name, "\\", "/")
+
+	switch {
+	case strings.ContainsRune(name, 0):
+		return "", fmt.Errorf("name contains NUL byte")
+	case strings.HasPrefix(name, "/"):
+		return "", fmt.Errorf("absolute path")
+	case len(name) >= 2 && name[1] == ':':
+		return "", fmt.Errorf("drive letter in path")
+	}
+
+	for _, part := range strings.Split(name, "/") {
+		if part == ".." {
+			return "", fmt.Errorf("path traversal")
+		}
+	}
+	return path.Clean(name), nil
+}
+
+// countEntry enforces the file count limit
+func (x *extractor) countEntry() error {
+	x.files++
+	if x.limits.MaxFiles > 0 && x.files > x.limits.MaxFiles {
+		return &UnsafeArchiveError{Reason: fmt.Sprintf("more than %d entries", x.limits.MaxFiles)}
+	}
+	return nil
+}
+
+func (x *extractor) mkdir(name string, mode os.FileMode) error {
+	fpath, err := x.entryPath(name)
+	if err != nil {
+		return err
+	}
+	if err := x.countEntry(); err != nil {
+		return err
+	}
+	return os.MkdirAll(fpath, dirMode(mode))
+}
+
+// writeFile copies an entry's contents, counting bytes actually written
+// rather than trusting sizes declared in the archive headers
+func (x *extractor) writeFile(name string, r io.Reader, perm os.FileMode) error {
+	fpath, err := x.entryPath(name)
+	if err != nil {
+		return err
+	}
+	if err := x.countEntry(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(fpath), 0755); err != nil {
+		return err
+	}
+
+	if x.limits.MaxSize > 0 {
+		// Read one byte past the budget so overflow is detectable
+		r = io.LimitReader(r, x.limits.MaxSize-x.written+1)
+	}
+
+	n, err := writeFile(fpath, r, perm)
+	x.written += n
+	if err != nil {
+		return err
+	}
+	if x.limits.MaxSize > 0 && x.written > x.limits.MaxSize {
+		return &UnsafeArchiveError{Reason: fmt.Sprintf("uncompressed size exceeds %s", FormatBytes(x.limits.MaxSize))}
+	}
+	return nil
+}
+
+// symlink queues a symbolic link. The target must stay inside the destination.
+func (x *extractor) symlink(name, target string) error {
+	fpath, err := x.entryPath(name)
+	if err != nil {
+		return err
+	}
+	if err := x.countEntry(); err != nil {
+		return err
+	}
+
+	target = strings.ReplaceAll(target, "\\", "/")
+	if target == "" || strings.HasPrefix(target, "/") || (len(target) >= 2 && target[1] == ':') {
+		return &UnsafeArchiveError{Entry: name, Reason: fmt.Sprintf("symlink to absolute path %q", target)}
+	}
+	resolved := filepath.Join(filepath.Dir(fpath), filepath.FromSlash(target))
+	if !withinDir(x.dest, resolved) {
+		return &UnsafeArchiveError{Entry: name, Reason: fmt.Sprintf("symlink escapes destination (%q)", target)}
+	}
+
+	x.links = append(x.links, pendingLink{entry: name, path: fpath, target: filepath.FromSlash(target)})
+	return nil
+}
+
+// hardlink queues a hard link to another entry of the archive
+func (x *extractor) hardlink(name, targetEntry string) error {
+	fpath, err := x.entryPath(name)
+	if err != nil {
+		return err
+	}
+	target, err := x.entryPath(targetEntry)
+	if err != nil {
+		return err
+	}
+	if err := x.countEntry(); err != nil {
+		return err
+	}
+
+	x.links = append(x.links, pendingLink{entry: name, path: fpath, target: target, hard: true})
+	return nil
+}
+
+// finish creates queued links once every regular file is in place. Links
+// may chain through each other ("a" -> "b/..", "b" -> "."), which the
+// lexical checks cannot see, so each link's parent must really be inside
+// the destination before the link is made, and each symlink must resolve
+// inside it once made.
+func (x *extractor) finish() error {
+	realDest, err := filepath.EvalSymlinks(x.dest)
+	if err != nil {
+		return err
+	}
+
+	for _, l := range x.links {
+		if !resolvesWithin(realDest, filepath.Dir(l.path)) {
+			return &UnsafeArchiveError{Entry: l.entry, Reason: "link is created through a symlink leading outside the destination"}
+		}
+		if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+			return err
+		}
+		_ = os.Remove(l.path)
+
+		if l.hard {
+			target, err := filepath.EvalSymlinks(l.target)
+			if err != nil || !withinDir(realDest, target) {
+				return &UnsafeArchiveError{Entry: l.entry, Reason: "hard link to a missing file or one outside the destination"}
+			}
+			info, err := os.Lstat(target)
+			if err != nil || !info.Mode().IsRegular() {
+				return &UnsafeArchiveError{Entry: l.entry, Reason: "hard link to a missing or non-regular file"}
+			}
+			if err := os.Link(target, l.path); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := os.Symlink(l.target, l.path); err != nil {
+			return err
+		}
+		if !resolvesWithin(realDest, l.path) {
+			_ = os.Remove(l.path)
+			return &UnsafeArchiveError{Entry: l.entry, Reason: "symlink escapes destination"}
+		}
+	}
+
+	// A later link can redirect an earlier one that was dangling when made
+	for _, l := range x.links {
+		if !l.hard && !resolvesWithin(realDest, l.path) {
+			_ = os.Remove(l.path)
+			return &UnsafeArchiveError{Entry: l.entry, Reason: "symlink escapes destination"}
+		}
+	}
+	return nil
+}
+
+// resolvesWithin reports whether p, or its nearest existing ancestor when p
+// cannot be resolved (a dangling link, a directory not created yet), is
+// inside realDir once symlinks are followed
+func resolvesWithin(realDir, p string) bool {
+	for {
+		if resolved, err := filepath.EvalSymlinks(p); err == nil {
+			return withinDir(realDir, resolved)
+		}
+		parent := filepath.Dir(p)
+		if parent == p {
+			return false
+		}
+		p = parent
+	}
+}
+
+// withinDir reports whether path is dir or inside it
+func withinDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+func extractZip(zipPath, destPath string, limits ExtractLimits) error {
+	r, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = r.Close() }()
+
+	x, err := newExtractor(destPath, limits)
+	if err != nil {
+		return err
+	}
+
+	// Find common prefix (many zips have a single root folder). Names are
+	// validated first so "../" or "C:/" can never be taken for a root folder.
+	names := make([]string, 0, len(r.File))
+	for _, f := range r.File {
+		if _, err := cleanEntryName(f.Name); err != nil {
+			return &UnsafeArchiveError{Entry: f.Name, Reason: err.Error()}
+		}
+		names = append(names, f.Name)
+	}
+	prefix := commonRootPrefix(names)
+
+	for _, f := range r.File {
+		name := stripRoot(f.Name, prefix)
+		if name == "" {
+			continue
+		}
+
+		if f.FileInfo().IsDir() {
+			if err := x.mkdir(name, f.Mode()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+
+		// Zips created on Unix may carry symlinks; the entry body is the link target
+		if f.Mode()&os.ModeSymlink != 0 {
+			target, err := io.ReadAll(io.LimitReader(rc, 4096))
+			_ = rc.Close()
+			if err != nil {
+				return err
+			}
+			if err := x.symlink(name, string(target)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !f.Mode().IsRegular() {
+			_ = rc.Close()
+			continue // Skip devices, pipes and other special files
+		}
+
+		err = x.writeFile(name, rc, f.Mode().Perm())
+		_ = rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return x.finish()
+}
+
+// openTar opens a compressed tarball for reading
+func openTar(archivePath, archiveType string) (*tar.Reader, io.Closer, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var r io.Reader
+	switch archiveType {
+	case ArchiveTarGz:
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			_ = f.Close()
+			return nil, nil, err
+		}
+		r = gz
+	case ArchiveTarXz:
+		xr, err := xz.NewReader(f)
+		if err != nil {
+			_ = f.Close()
+			return nil, nil, err
+		}
+		r = xr
+	default:
+		_ = f.Close()
+		return nil, nil, fmt.Errorf("unsupported archive type: %s", archiveType)
+	}
+
+	return tar.NewReader(r), f, nil
+}
+
+// tarEntryName normalizes a tar entry name ("./bin/java" -> "bin/java")
+func tarEntryName(name string) string {
+	name = strings.TrimPrefix(name, "./")
+	if name == "." {
+		return ""
+	}
+	return name
+}
+
+func extractTar(archivePath, destPath, archiveType string, limits ExtractLimits) error {
+	// First pass: collect entry names to find a common root folder.
+	// Tar is a stream format, so this means decompressing twice.
+	tr, closer, err := openTar(archivePath, archiveType)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			_ = closer.Close()
+			return err
+		}
+		// Skip pax global headers, they are not files
+		if hdr.Typeflag == tar.TypeXGlobalHeader {
+			continue
+		}
+		// Validate raw names before the root folder is stripped, so "../" or
+		// "C:/" can never be mistaken for a common root
+		if _, err := cleanEntryName(hdr.Name); err != nil {
+			_ = closer.Close()
+			return &UnsafeArchiveError{Entry: hdr.Name, Reason: err.Error()}
+		}
+		if name := tarEntryName(hdr.Name); name != "" {
+			names = append(names, name)
+		}
+		if limits.MaxFiles > 0 && len(names) > limits.MaxFiles {
+			_ = closer.Close()
+			return &UnsafeArchiveError{Reason: fmt.Sprintf("more than %d entries", limits.MaxFiles)}
+		}
+	}
+	_ = closer.Close()
+	prefix := commonRootPrefix(names)
+
+	// Second pass: extract
+	tr, closer, err = openTar(archivePath, archiveType)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = closer.Close() }()
+
+	x, err := newExtractor(destPath, limits)
+	if err != nil {
+		return err
+	}
+
+	stripPrefix := func(name string) string {
+		return stripRoot(tarEntryName(name), prefix)
+	}
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		name := stripPrefix(hdr.Name)
+		if name == "" {
+			continue
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = x.mkdir(name, hdr.FileInfo().Mode())
+		case tar.TypeReg:
+			err = x.writeFile(name, tr, hdr.FileInfo().Mode().Perm())
+		case tar.TypeSymlink:
+			err = x.symlink(name, hdr.Linkname)
+		case tar.TypeLink:
+			// Hard link names are archive paths, so strip the root folder too
+			err = x.hardlink(name, stripPrefix(hdr.Linkname))
+		default:
+			// Skip device files, FIFOs and pax headers
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return x.finish()
+}
+
+// dirMode makes sure extracted directories stay traversable by the owner
+func dirMode(mode os.FileMode) os.FileMode {
+	return mode.Perm() | 0700
+}
+
+// writeFile copies r into a new file at path with the given permissions,
+// returning the number of bytes written
+func writeFile(path string, r io.Reader, perm os.FileMode) (int64, error) {
+	if perm == 0 {
+		perm = 0644
+	}
+
+	outFile, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := io.Copy(outFile, r)
+	if closeErr := outFile.Close(); err == nil {
+		err = closeErr
+	}
+	return n, err
+}
@@ -0,0 +1,450 @@
+package version
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/ulikunitz/xz"
+)
+
+// tarEntry describes a file to place in a test tarball
+type tarEntry struct {
+	name     string
+	body     string
+	mode     int64
+	typeflag byte
+	linkname string
+}
+
+// writeTestTar writes a tar.gz or tar.xz archive with the given entries
+func writeTestTar(t *testing.T, path, archiveType string, entries []tarEntry) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		hdr := &tar.Header{
+			Name:     e.name,
+			Mode:     e.mode,
+			Size:     int64(len(e.body)),
+			Typeflag: e.typeflag,
+			Linkname: e.linkname,
+		}
+		if e.typeflag != tar.TypeReg {
+			hdr.Size = 0
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("Failed to write tar header: %v", err)
+		}
+		if hdr.Size > 0 {
+			_, _ = tw.Write([]byte(e.body))
+		}
+	}
+	_ = tw.Close()
+
+	var out bytes.Buffer
+	var w io.WriteCloser
+	switch archiveType {
+	case ArchiveTarGz:
+		w = gzip.NewWriter(&out)
+	case ArchiveTarXz:
+		xw, err := xz.NewWriter(&out)
+		if err != nil {
+			t.Fatalf("Failed to create xz writer: %v", err)
+		}
+		w = xw
+	}
+	_, _ = w.Write(buf.Bytes())
+	_ = w.Close()
+
+	if err := os.WriteFile(path, out.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write archive: %v", err)
+	}
+}
+
+// writeTestZip writes a zip archive with the given file contents
+func writeTestZip(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create zip: %v", err)
+	}
+	zw := zip.NewWriter(f)
+	for name, body := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to add %s: %v", name, err)
+		}
+		_, _ = w.Write([]byte(body))
+	}
+	_ = zw.Close()
+	_ = f.Close()
+}
+
+func jdkEntries() []tarEntry {
+	return []tarEntry{
+		{name: "jdk-21/", mode: 0755, typeflag: tar.TypeDir},
+		{name: "jdk-21/bin/", mode: 0755, typeflag: tar.TypeDir},
+		{name: "jdk-21/bin/java", body: "#!/bin/sh\n", mode: 0755, typeflag: tar.TypeReg},
+		{name: "jdk-21/release", body: "JAVA_VERSION=21", mode: 0644, typeflag: tar.TypeReg},
+		{name: "jdk-21/lib/", mode: 0755, typeflag: tar.TypeDir},
+		{name: "jdk-21/lib/libjvm.so", body: "elf", mode: 0644, typeflag: tar.TypeReg},
+		{name: "jdk-21/lib/current", typeflag: tar.TypeSymlink, linkname: "libjvm.so"},
+		{name: "jdk-21/bin/javac", typeflag: tar.TypeLink, linkname: "jdk-21/bin/java"},
+	}
+}
+
+func TestExtractTar(t *testing.T) {
+	for _, archiveType := range []string{ArchiveTarGz, ArchiveTarXz} {
+		t.Run(archiveType, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			archivePath := filepath.Join(tmpDir, "jdk."+archiveType)
+			destPath := filepath.Join(tmpDir, "out")
+			writeTestTar(t, archivePath, archiveType, jdkEntries())
+
+			if err := extractArchive(archivePath, destPath, archiveType, DefaultExtractLimits()); err != nil {
+				t.Fatalf("extractArchive failed: %v", err)
+			}
+
+			// Root folder should be stripped
+			data, err := os.ReadFile(filepath.Join(destPath, "release"))
+			if err != nil {
+				t.Fatalf("Expected release at root: %v", err)
+			}
+			if string(data) != "JAVA_VERSION=21" {
+				t.Errorf("Unexpected release content: %q", data)
+			}
+
+			// Hard link resolves against the stripped path
+			if _, err := os.Stat(filepath.Join(destPath, "bin", "javac")); err != nil {
+				t.Errorf("Expected hard link bin/javac: %v", err)
+			}
+
+			if runtime.GOOS == "windows" {
+				return
+			}
+
+			info, err := os.Stat(filepath.Join(destPath, "bin", "java"))
+			if err != nil {
+				t.Fatalf("Expected bin/java: %v", err)
+			}
+			if info.Mode().Perm()&0100 == 0 {
+				t.Errorf("Expected bin/java to be executable, got %v", info.Mode())
+			}
+
+			target, err := os.Readlink(filepath.Join(destPath, "lib", "current"))
+			if err != nil {
+				t.Fatalf("Expected symlink lib/current: %v", err)
+			}
+			if target != "libjvm.so" {
+				t.Errorf("Expected symlink target libjvm.so, got %s", target)
+			}
+		})
+	}
+}
+
+func TestExtractTarDotSlashEntries(t *testing.T) {
+	tmpDir := t.TempDir()
+	archivePath := filepath.Join(tmpDir, "tool.tar.gz")
+	destPath := filepath.Join(tmpDir, "out")
+	writeTestTar(t, archivePath, ArchiveTarGz, []tarEntry{
+		{name: "./", mode: 0755, typeflag: tar.TypeDir},
+		{name: "./go/", mode: 0755, typeflag: tar.TypeDir},
+		{name: "./go/VERSION", body: "go1.22.0", mode: 0644, typeflag: tar.TypeReg},
+	})
+
+	if err := extractArchive(archivePath, destPath, ArchiveTarGz, DefaultExtractLimits()); err != nil {
+		t.Fatalf("extractArchive failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(destPath, "VERSION")); err != nil {
+		t.Errorf("Expected VERSION at root after stripping ./go/: %v", err)
+	}
+}
+
+func TestExtractZipStripsRoot(t *testing.T) {
+	tmpDir := t.TempDir()
+	archivePath := filepath.Join(tmpDir, "tool.zip")
+	destPath := filepath.Join(tmpDir, "out")
+	writeTestZip(t, archivePath, map[string]string{
+		"gradle-8.5/bin/gradle": "script",
+		"gradle-8.5/LICENSE":    "license",
+	})
+
+	if err := extractArchive(archivePath, destPath, ArchiveZip, DefaultExtractLimits()); err != nil {
+		t.Fatalf("extractArchive failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(destPath, "bin", "gradle")); err != nil {
+		t.Errorf("Expected bin/gradle at root: %v", err)
+	}
+}
+
+func TestCommonRootPrefix(t *testing.T) {
+	tests := []struct {
+		names    []string
+		expected string
+	}{
+		{[]string{"jdk/", "jdk/bin/java"}, "jdk/"},
+		{[]string{"jdk", "jdk/bin/java"}, "jdk/"},
+		{[]string{"jdk/bin/java", "other/file"}, ""},
+		{[]string{"README", "bin/tool"}, ""},
+		{[]string{"mill"}, ""},
+		{nil, ""},
+	}
+
+	for _, tt := range tests {
+		if result := commonRootPrefix(tt.names); result != tt.expected {
+			t.Errorf("commonRootPrefix(%v): expected %q, got %q", tt.names, tt.expected, result)
+		}
+	}
+}
+
+func TestDetectArchiveType(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	zipPath := filepath.Join(tmpDir, "download")
+	writeTestZip(t, zipPath, map[string]string{"a/b": "c"})
+	gzPath := filepath.Join(tmpDir, "download-gz")
+	writeTestTar(t, gzPath, ArchiveTarGz, []tarEntry{{name: "a", body: "b", mode: 0644, typeflag: tar.TypeReg}})
+	xzPath := filepath.Join(tmpDir, "download-xz")
+	writeTestTar(t, xzPath, ArchiveTarXz, []tarEntry{{name: "a", body: "b", mode: 0644, typeflag: tar.TypeReg}})
+
+	tests := []struct {
+		url         string
+		contentType string
+		file        string
+		expected    string
+	}{
+		{"https://nodejs.org/dist/v20.0.0/node-v20.0.0-linux-x64.tar.xz", "", "", ArchiveTarXz},
+		{"https://go.dev/dl/go1.22.0.linux-amd64.tar.gz", "", "", ArchiveTarGz},
+		{"https://example.com/tool.tgz?x=1", "", "", ArchiveTarGz},
+		{"https://example.com/tool.ZIP", "", "", ArchiveZip},
+		{"https://api.example.com/binary", "application/zip", "", ArchiveZip},
+		{"https://api.example.com/binary", "application/x-gzip; charset=binary", "", ArchiveTarGz},
+		{"https://api.example.com/binary", "application/octet-stream", zipPath, ArchiveZip},
+		{"https://api.example.com/binary", "", gzPath, ArchiveTarGz},
+		{"https://api.example.com/binary", "", xzPath, ArchiveTarXz},
+	}
+
+	for _, tt := range tests {
+		result, err := DetectArchiveType(tt.url, tt.contentType, tt.file)
+		if err != nil {
+			t.Errorf("DetectArchiveType(%q, %q): unexpected error %v", tt.url, tt.contentType, err)
+			continue
+		}
+		if result != tt.expected {
+			t.Errorf("DetectArchiveType(%q, %q): expected %q, got %q", tt.url, tt.contentType, tt.expected, result)
+		}
+	}
+
+	if _, err := DetectArchiveType("https://example.com/unknown", "text/html", filepath.Join(tmpDir, "missing")); err == nil {
+		t.Error("Expected error for undetectable archive type")
+	}
+}
+
+// writeTestZipEntries writes a zip archive preserving entry order and modes
+func writeTestZipEntries(t *testing.T, path string, entries []tarEntry) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create zip: %v", err)
+	}
+	zw := zip.NewWriter(f)
+	for _, e := range entries {
+		hdr := &zip.FileHeader{Name: e.name, Method: zip.Deflate}
+		hdr.SetMode(0644)
+		body := e.body
+		if e.typeflag == tar.TypeSymlink {
+			hdr.SetMode(os.ModeSymlink | 0777)
+			body = e.linkname
+		}
+		w, err := zw.CreateHeader(hdr)
+		if err != nil {
+			t.Fatalf("Failed to add %s: %v", e.name, err)
+		}
+		_, _ = w.Write([]byte(body))
+	}
+	_ = zw.Close()
+	_ = f.Close()
+}
+
+func TestExtractRejectsUnsafeEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []tarEntry
+	}{
+		{"parent traversal", []tarEntry{
+			{name: "tool/bin/tool", body: "ok", mode: 0755, typeflag: tar.TypeReg},
+			{name: "tool/../../evil", body: "x", mode: 0644, typeflag: tar.TypeReg},
+		}},
+		{"absolute path", []tarEntry{
+			{name: "/tmp/evil", body: "x", mode: 0644, typeflag: tar.TypeReg},
+		}},
+		{"drive letter", []tarEntry{
+			{name: "C:/Windows/evil", body: "x", mode: 0644, typeflag: tar.TypeReg},
+		}},
+		{"backslash traversal", []tarEntry{
+			{name: "..\\evil", body: "x", mode: 0644, typeflag: tar.TypeReg},
+		}},
+		{"escaping symlink", []tarEntry{
+			{name: "lib/", mode: 0755, typeflag: tar.TypeDir},
+			{name: "bin/up", typeflag: tar.TypeSymlink, linkname: "../../outside"},
+		}},
+		{"absolute symlink", []tarEntry{
+			{name: "bin/", mode: 0755, typeflag: tar.TypeDir},
+			{name: "etc/passwd", typeflag: tar.TypeSymlink, linkname: "/etc/passwd"},
+		}},
+		{"hard link outside", []tarEntry{
+			{name: "bin/", mode: 0755, typeflag: tar.TypeDir},
+			{name: "bin/shadow", typeflag: tar.TypeLink, linkname: "../etc/shadow"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			archivePath := filepath.Join(tmpDir, "evil.tar.gz")
+			destPath := filepath.Join(tmpDir, "sub", "out")
+			writeTestTar(t, archivePath, ArchiveTarGz, tt.entries)
+
+			err := extractArchive(archivePath, destPath, ArchiveTarGz, DefaultExtractLimits())
+			var unsafeErr *UnsafeArchiveError
+			if !errors.As(err, &unsafeErr) {
+				t.Fatalf("Expected UnsafeArchiveError, got %v", err)
+			}
+
+			for _, p := range []string{"evil", "outside", filepath.Join("sub", "evil")} {
+				if _, err := os.Lstat(filepath.Join(tmpDir, p)); err == nil {
+					t.Errorf("Entry escaped destination: %s", p)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractZipRejectsUnsafeEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []tarEntry
+	}{
+		{"parent traversal", []tarEntry{{name: "../evil", body: "x"}}},
+		{"escaping symlink", []tarEntry{
+			{name: "tool/bin/run", body: "ok"},
+			{name: "tool/link", typeflag: tar.TypeSymlink, linkname: "../../.."},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			archivePath := filepath.Join(tmpDir, "evil.zip")
+			writeTestZipEntries(t, archivePath, tt.entries)
+
+			err := extractArchive(archivePath, filepath.Join(tmpDir, "out"), ArchiveZip, DefaultExtractLimits())
+			var unsafeErr *UnsafeArchiveError
+			if !errors.As(err, &unsafeErr) {
+				t.Fatalf("Expected UnsafeArchiveError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestExtractRejectsChainedSymlinkEscape(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks require privileges on Windows")
+	}
+
+	// Each link is harmless on its own, but together they walk out of dest
+	tests := []struct {
+		name    string
+		entries []tarEntry
+	}{
+		{"link resolving outside", []tarEntry{
+			{name: "b/", mode: 0755, typeflag: tar.TypeDir},
+			{name: "a", typeflag: tar.TypeSymlink, linkname: "."},
+			{name: "b/c", typeflag: tar.TypeSymlink, linkname: "../a/a/a"},
+			{name: "b/d", typeflag: tar.TypeSymlink, linkname: "c/../.."},
+		}},
+		{"link created through an escaping link", []tarEntry{
+			{name: "b", typeflag: tar.TypeSymlink, linkname: "."},
+			{name: "a", typeflag: tar.TypeSymlink, linkname: "b/.."},
+			{name: "a/victim", typeflag: tar.TypeSymlink, linkname: "gone"},
+		}},
+		{"hard link through an escaping link", []tarEntry{
+			{name: "b", typeflag: tar.TypeSymlink, linkname: "."},
+			{name: "a", typeflag: tar.TypeSymlink, linkname: "b/.."},
+			{name: "x", typeflag: tar.TypeLink, linkname: "a/victim"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			victim := filepath.Join(tmpDir, "victim")
+			if err := os.WriteFile(victim, []byte("keep"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			archivePath := filepath.Join(tmpDir, "chain.tar.gz")
+			writeTestTar(t, archivePath, ArchiveTarGz, tt.entries)
+
+			err := extractArchive(archivePath, filepath.Join(tmpDir, "out"), ArchiveTarGz, DefaultExtractLimits())
+			var unsafeErr *UnsafeArchiveError
+			if !errors.As(err, &unsafeErr) {
+				t.Fatalf("Expected UnsafeArchiveError, got %v", err)
+			}
+
+			// Nothing outside dest may have been touched on the way
+			info, err := os.Lstat(victim)
+			if err != nil || !info.Mode().IsRegular() {
+				t.Fatalf("File outside dest was replaced: %v", err)
+			}
+			if content, _ := os.ReadFile(victim); string(content) != "keep" {
+				t.Errorf("File outside dest changed to %q", content)
+			}
+			entries, _ := os.ReadDir(tmpDir)
+			if len(entries) != 3 {
+				t.Errorf("Entries next to dest = %v, want victim, chain.tar.gz and out", entries)
+			}
+		})
+	}
+}
+
+func TestExtractLimits(t *testing.T) {
+	tmpDir := t.TempDir()
+	archivePath := filepath.Join(tmpDir, "jdk.tar.gz")
+	writeTestTar(t, archivePath, ArchiveTarGz, jdkEntries())
+
+	tests := []struct {
+		name   string
+		limits ExtractLimits
+	}{
+		{"size", ExtractLimits{MaxSize: 16}},
+		{"file count", ExtractLimits{MaxFiles: 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := extractArchive(archivePath, filepath.Join(tmpDir, tt.name), ArchiveTarGz, tt.limits)
+			var unsafeErr *UnsafeArchiveError
+			if !errors.As(err, &unsafeErr) {
+				t.Fatalf("Expected UnsafeArchiveError, got %v", err)
+			}
+		})
+	}
+
+	// Exactly at the limit is fine: 10 + 15 + 3 bytes of file content
+	if err := extractArchive(archivePath, filepath.Join(tmpDir, "ok"), ArchiveTarGz, ExtractLimits{MaxSize: 28}); err != nil {
+		t.Errorf("Expected extraction within limits to succeed: %v", err)
+	}
+}
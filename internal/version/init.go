@@ -0,0 +1,176 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/azdren/verman/internal/config"
+)
+
+// initTemplate is the shell-specific part of an init script; the variables
+// and PATH entries come from GlobalEnv and are rendered with the Shell's
+// statements. In the strings, %[1]s is the quoted verman executable and
+// %[2]s the shell name.
+type initTemplate struct {
+	header     string
+	prelude    string // Setup needed before the statements
+	shims      bool   // Put the shim directory on PATH rather than each version's directories
+	completion string
+	hook       string // Directory change hook, added with --hook
+	footer     string
+}
+
+var initTemplates = map[Shell]initTemplate{
+	ShellPwsh: {
+		header: "# Verman PowerShell Integration\n# Add this to your PowerShell profile ($PROFILE)\n\n",
+		footer: `# Auto-detect versions on directory change
+function Set-VermanVersions {
+    $detected = & verman detect --quiet --json 2>$null | ConvertFrom-Json
+    if ($detected) {
+        foreach ($item in $detected) {
+            & verman use $item.language $item.version --quiet 2>$null
+        }
+    }
+}
+
+# Hook into directory change (optional - uncomment to enable)
+# $ExecutionContext.SessionState.InvokeCommand.PreCommandLookupAction = {
+#     param($CommandName, $CommandLookupEventArgs)
+#     if ($CommandName -eq 'cd' -or $CommandName -eq 'Set-Location') {
+#         Set-VermanVersions
+#     }
+# }
+`,
+	},
+	ShellCmd: {
+		header: "@echo off\nREM Verman CMD Integration\n\n",
+	},
+	ShellBash: {
+		header:     "# Verman Bash Integration\n# Add this to ~/.bashrc: eval \"$(%[1]s init bash)\"\n\n",
+		shims:      true,
+		completion: "eval \"$(%[1]s completion bash)\"\n",
+		hook: `_verman_hook() {
+  if [ "$PWD" != "${_VERMAN_PWD-}" ]; then
+    _VERMAN_PWD=$PWD
+    eval "$(%[1]s hook bash)"
+  fi
+}
+case ";${PROMPT_COMMAND-};" in
+  *";_verman_hook;"*) ;;
+  *) PROMPT_COMMAND="_verman_hook${PROMPT_COMMAND:+;$PROMPT_COMMAND}" ;;
+esac
+`,
+	},
+	ShellZsh: {
+		header:     "# Verman Zsh Integration\n# Add this to ~/.zshrc: eval \"$(%[1]s init zsh)\"\n\n",
+		shims:      true,
+		completion: "(( $+functions[compdef] )) && eval \"$(%[1]s completion zsh)\"\n",
+		hook: `_verman_hook() {
+  eval "$(%[1]s hook zsh)"
+}
+autoload -Uz add-zsh-hook
+add-zsh-hook chpwd _verman_hook
+_verman_hook
+`,
+	},
+	ShellFish: {
+		header:     "# Verman Fish Integration\n# Add this to ~/.config/fish/config.fish: %[1]s init fish | source\n\n",
+		shims:      true,
+		completion: "%[1]s completion fish | source\n",
+		hook: `function _verman_hook --on-variable PWD
+    %[1]s hook fish | source
+end
+_verman_hook
+`,
+	},
+	ShellNu: {
+		header: `# Verman Nushell Integration
+# Save this as an autoload module, and again after installing a new language:
+#   mkdir ($nu.default-config-dir | path join autoload)
+#   verman init nu | save -f ($nu.default-config-dir | path join autoload verman.nu)
+
+`,
+		prelude: "use std/util \"path add\"\n\n",
+		shims:   true,
+		hook: `$env.config.hooks.env_change.PWD = (
+    $env.config.hooks.env_change.PWD? | default [] | append {|before, after|
+        ^%[1]s hook nu | from nuon | load-env
+    }
+)
+^%[1]s hook nu | from nuon | load-env
+`,
+	},
+	ShellElvish: {
+		header: "# Verman Elvish Integration\n# Add this to ~/.config/elvish/rc.elv: eval (%[1]s init elvish | slurp)\n\n",
+		shims:  true,
+		hook: `set after-chdir = [$@after-chdir {|_| eval (%[1]s hook elvish | slurp) }]
+eval (%[1]s hook elvish | slurp)
+`,
+	},
+}
+
+// GenerateInit generates the shell integration script for a shell: the
+// global versions' variables, their PATH directories (or, for Unix shells,
+// the shim directory), completion and, with hook set, a directory change
+// hook that points JAVA_HOME & co. at the project's versions. exe is the
+// verman executable the script calls.
+func GenerateInit(cfg *config.Config, sh Shell, exe string, hook bool) (string, error) {
+	tmpl, ok := initTemplates[sh]
+	if !ok {
+		return "", fmt.Errorf("no shell integration for %s", sh)
+	}
+	if hook && tmpl.hook == "" {
+		return "", fmt.Errorf("--hook is not supported for %s", sh)
+	}
+
+	var sb strings.Builder
+	expand := func(s string) {
+		if strings.Contains(s, "%[") {
+			s = fmt.Sprintf(s, sh.Quote(exe), sh)
+		}
+		sb.WriteString(s)
+	}
+
+	expand(tmpl.header)
+	sb.WriteString(tmpl.prelude)
+
+	if tmpl.shims {
+		// Shims pick each tool's version per directory
+		if shimDir, err := ShimDir(); err == nil {
+			sb.WriteString(sh.Comment("Shims") + "\n")
+			sb.WriteString(sh.PrependPath(shimDir) + "\n\n")
+		}
+	}
+
+	for _, env := range GlobalEnv(cfg) {
+		if tmpl.shims && len(env.Vars) == 0 {
+			continue
+		}
+		sb.WriteString(sh.Comment(env.Language) + "\n")
+		sb.WriteString(sh.Statements(env.Vars))
+		if !tmpl.shims {
+			for _, dir := range env.Path {
+				sb.WriteString(sh.PrependPath(dir) + "\n")
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	if tmpl.completion != "" {
+		sb.WriteString(sh.Comment("Completion") + "\n")
+		expand(tmpl.completion)
+		sb.WriteString("\n")
+	}
+
+	if tmpl.hook != "" {
+		if hook {
+			sb.WriteString(sh.Comment("Point JAVA_HOME & co. at the project's versions on directory change") + "\n")
+			expand(tmpl.hook)
+		} else {
+			sb.WriteString(sh.Comment(fmt.Sprintf("Run \"verman init %s --hook\" to also switch JAVA_HOME & co. per project on cd", sh)) + "\n")
+		}
+	}
+
+	expand(tmpl.footer)
+	return sb.String(), nil
+}
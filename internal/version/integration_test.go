@@ -23,6 +23,7 @@ func TestIntegration_FullWorkflow(t *testing.T) {
 
 	// Setup sandbox
 	homeDir := t.TempDir()
+	t.Setenv("USERPROFILE", homeDir) // Shims go to ~/.verman/bin
 	vermanDir := filepath.Join(homeDir, ".verman")
 	versionsDir := filepath.Join(vermanDir, "versions")
 	projectsDir := filepath.Join(homeDir, "projects")
@@ -61,7 +62,7 @@ func TestIntegration_FullWorkflow(t *testing.T) {
 	}
 
 	// Step 3: Use Java 17
-	if err := mgr.Use("java", "17", false); err != nil {
+	if _, err := mgr.Use("java", "17", false); err != nil {
 		t.Fatalf("Failed to use Java 17: %v", err)
 	}
 
@@ -85,7 +86,7 @@ func TestIntegration_FullWorkflow(t *testing.T) {
 	}
 
 	// Step 6: Switch to detected version
-	if err := mgr.Use("java", detected.Version, false); err != nil {
+	if _, err := mgr.Use("java", detected.Version, false); err != nil {
 		t.Fatalf("Failed to switch to detected version: %v", err)
 	}
 
@@ -120,6 +121,7 @@ func TestIntegration_MultiLanguageProject(t *testing.T) {
 	}
 
 	homeDir := t.TempDir()
+	t.Setenv("USERPROFILE", homeDir) // Shims go to ~/.verman/bin
 	versionsDir := filepath.Join(homeDir, ".verman", "versions")
 
 	// Create versions directories for supported languages
@@ -162,7 +164,7 @@ func TestIntegration_MultiLanguageProject(t *testing.T) {
 
 	// Apply all detected versions
 	for _, d := range detected {
-		if err := mgr.Use(d.Language, d.Version, false); err != nil {
+		if _, err := mgr.Use(d.Language, d.Version, false); err != nil {
 			t.Errorf("Failed to use %s %s: %v", d.Language, d.Version, err)
 		}
 	}
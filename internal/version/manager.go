@@ -1,17 +1,19 @@
 package version
 
 import (
-	"archive/zip"
 	"fmt"
-	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 
 	"github.com/azdren/verman/internal/config"
 	"github.com/azdren/verman/internal/languages"
+	"github.com/azdren/verman/internal/lock"
+	"github.com/azdren/verman/internal/semver"
+	"github.com/azdren/verman/internal/sources"
 )
 
 type Manager struct {
@@ -22,6 +24,12 @@ func NewManager(cfg *config.Config) *Manager {
 	return &Manager{Config: cfg}
 }
 
+// lockLanguage serializes installs, switches and uninstalls of one language
+// across verman processes
+func (m *Manager) lockLanguage(langName string) (*lock.Lock, error) {
+	return lock.Acquire(m.Config.LockPath(langName))
+}
+
 // ListInstalled returns all installed versions for a language
 func (m *Manager) ListInstalled(langName string) ([]string, error) {
 	langPath := filepath.Join(m.Config.RootPath, langName)
@@ -60,34 +68,109 @@ func (m *Manager) GetCurrent(langName string) (string, error) {
 	return filepath.Base(target), nil
 }
 
-// Use switches to a specific version
-func (m *Manager) Use(langName, version string, global bool) error {
+// ResolveInstalled resolves a version range such as "^20.10" or ">=17 <22",
+// or a channel alias ("latest", "lts", "lts/iron"), to the newest installed
+// version it matches. A distribution suffix ("^21-amzn", "lts-amzn")
+// restricts the match to that distribution. User-defined aliases are
+// expanded first. Other versions are returned unchanged.
+func (m *Manager) ResolveInstalled(langName, version string) (string, error) {
+	version = m.ExpandAlias(langName, version)
+	expr, dist := sources.ParseVersionAndDistribution(version)
+	src, _ := sources.Get(langName)
+
+	var matches func(string) bool
+	var c *semver.Constraint
+	switch {
+	case sources.IsChannel(expr) && src != nil:
+		inChannel, err := src.ChannelFilter(expr)
+		if err != nil {
+			return "", err
+		}
+		matches = inChannel
+	case semver.IsConstraint(expr):
+		var err error
+		if c, err = semver.ParseConstraint(expr); err != nil {
+			return "", err
+		}
+		matches = c.Check
+	default:
+		return version, nil
+	}
+
+	installed, err := m.ListInstalled(langName)
+	if err != nil {
+		return "", err
+	}
+	semver.Sort(installed)
+
+	// Versions installed without a suffix use the default distribution
+	defaultDist := ""
+	if src != nil {
+		defaultDist = src.DefaultDist
+	}
+	sameDist := func(d string) bool {
+		if d == "" {
+			d = defaultDist
+		}
+		return dist == "" || sources.NormalizeDistribution(d) == sources.NormalizeDistribution(dist)
+	}
+
+	var candidates []string
+	for _, v := range installed {
+		base, d := sources.ParseVersionAndDistribution(v)
+		if !sameDist(d) {
+			continue
+		}
+		if matches(base) {
+			return v, nil
+		}
+		candidates = append(candidates, v)
+	}
+
+	if c != nil {
+		if nearest := semver.Nearest(candidates, c, 5); len(nearest) > 0 {
+			return "", fmt.Errorf("no installed %s version satisfies %s (nearest installed: %s)", langName, version, strings.Join(nearest, ", "))
+		}
+	}
+	return "", fmt.Errorf("no installed %s version satisfies %s", langName, version)
+}
+
+// Use switches to a specific version and returns the installed version it
+// switched to. Aliases and ranges are resolved against the installed
+// versions (see ResolveInstalled).
+func (m *Manager) Use(langName, version string, global bool) (string, error) {
 	lang, ok := languages.Get(langName)
 	if !ok {
-		return fmt.Errorf("unknown language: %s", langName)
+		return "", fmt.Errorf("unknown language: %s", langName)
+	}
+
+	version, err := m.ResolveInstalled(langName, version)
+	if err != nil {
+		return "", err
 	}
 
 	// Check dependencies and warn if missing
 	m.checkAndWarnDependencies(lang)
 
+	l, err := m.lockLanguage(langName)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = l.Release() }()
+
 	versionPath := m.Config.GetVersionPath(langName, version)
 	if _, err := os.Stat(versionPath); os.IsNotExist(err) {
-		return fmt.Errorf("version %s not installed for %s", version, langName)
+		return "", fmt.Errorf("version %s not installed for %s", version, langName)
 	}
 
 	currentPath := m.Config.GetCurrentPath(langName)
-
-	// Remove existing junction/symlink (ignore errors if doesn't exist)
-	removeJunction(currentPath)
-
-	// Create junction point (Windows) or symlink (Unix)
-	if err := createJunction(currentPath, versionPath); err != nil {
-		return err
+	if err := switchJunction(currentPath, versionPath); err != nil {
+		return "", err
 	}
 
 	// Update config
 	if err := m.Config.SetCurrentVersion(langName, version); err != nil {
-		return err
+		return "", err
 	}
 
 	// Create shims for this language
@@ -98,7 +181,7 @@ func (m *Manager) Use(langName, version string, global bool) error {
 
 	// If global, update persistent environment variables
 	if global {
-		return m.SetGlobalEnv(lang, currentPath)
+		return version, m.SetGlobalEnv(lang, currentPath)
 	}
 
 	// For Java, set JAVA_HOME in current process and check if globally set
@@ -124,55 +207,29 @@ func (m *Manager) Use(langName, version string, global bool) error {
 		}
 	}
 
-	return nil
+	return version, nil
 }
 
-// CreateShims creates wrapper scripts in ~/.verman/bin for all executables
-func (m *Manager) CreateShims(langName, currentPath string, pathDirs []string) error {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return err
-	}
-	shimDir := filepath.Join(home, ".verman", "bin")
-
-	// Ensure shim directory exists
-	if err := os.MkdirAll(shimDir, 0755); err != nil {
-		return err
-	}
-
-	// Find all executables in the path dirs
-	for _, relDir := range pathDirs {
-		binDir := currentPath
-		if relDir != "." {
-			binDir = filepath.Join(currentPath, relDir)
-		}
-
-		entries, err := os.ReadDir(binDir)
-		if err != nil {
-			continue
+// switchJunction points the "current" link at a new version. On Unix the new
+// symlink is renamed over the old one, so "current" never goes missing.
+func switchJunction(currentPath, versionPath string) error {
+	if runtime.GOOS != "windows" {
+		tmpPath := fmt.Sprintf("%s.%d.tmp", currentPath, os.Getpid())
+		_ = os.Remove(tmpPath)
+		if err := createJunction(tmpPath, versionPath); err != nil {
+			return err
 		}
-
-		for _, entry := range entries {
-			name := entry.Name()
-			// Only create shims for .exe, .cmd, .bat files on Windows
-			ext := strings.ToLower(filepath.Ext(name))
-			if ext != ".exe" && ext != ".cmd" && ext != ".bat" {
-				continue
-			}
-
-			baseName := strings.TrimSuffix(name, ext)
-			shimPath := filepath.Join(shimDir, baseName+".cmd")
-			targetPath := filepath.Join(binDir, name)
-
-			// Create shim script
-			shimContent := fmt.Sprintf("@echo off\r\n\"%s\" %%*\r\n", targetPath)
-			if err := os.WriteFile(shimPath, []byte(shimContent), 0755); err != nil {
-				continue // Skip on error, non-fatal
-			}
+		if err := os.Rename(tmpPath, currentPath); err != nil {
+			_ = os.Remove(tmpPath)
+			return fmt.Errorf("failed to switch current version: %w", err)
 		}
+		return nil
 	}
 
-	return nil
+	// Junctions cannot be renamed over each other; the language lock keeps
+	// other verman processes out of the gap
+	removeJunction(currentPath)
+	return createJunction(currentPath, versionPath)
 }
 
 // removeJunction removes a junction point or symlink
@@ -242,26 +299,29 @@ func createJunction(linkPath, targetPath string) error {
 	return nil
 }
 
-// SetGlobalEnv updates persistent environment variables
+// SetGlobalEnv updates persistent environment variables: in the user
+// environment on Windows, else in the managed block of the shell profile
+// (a fish universal variable for fish)
 func (m *Manager) SetGlobalEnv(lang languages.Language, currentPath string) error {
-	if runtime.GOOS != "windows" {
-		return fmt.Errorf("global env setting only supported on Windows")
-	}
-
-	// Set HOME-style variables
-	for envVar, relPath := range lang.EnvVars() {
-		fullPath := currentPath
-		if relPath != "." {
-			fullPath = filepath.Join(currentPath, relPath)
+	for _, env := range NewLanguageEnv(lang, currentPath).Vars {
+		if err := setUserEnvVar(env.Name, env.Value); err != nil {
+			return fmt.Errorf("failed to set %s: %w", env.Name, err)
 		}
+	}
+	return nil
+}
 
-		cmd := exec.Command("setx", envVar, fullPath)
-		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("failed to set %s: %w", envVar, err)
+// RemoveGlobalEnv removes the variables SetGlobalEnv persisted and returns
+// where it removed them from
+func (m *Manager) RemoveGlobalEnv() ([]string, error) {
+	var names []string
+	for _, lang := range languages.All() {
+		for envVar := range lang.EnvVars() {
+			names = append(names, envVar)
 		}
 	}
-
-	return nil
+	sort.Strings(names)
+	return removeUserEnv(names, m.Config.RootPath)
 }
 
 // checkAndWarnDependencies checks if dependencies are installed and warns if not
@@ -292,7 +352,30 @@ func (m *Manager) Install(langName, version string) error {
 	return m.InstallWithDist(langName, version, "")
 }
 
-// InstallWithDist downloads and installs a version with a specific distribution
+// ResolveInstallVersion resolves what "verman install" was given (a partial
+// version, range, channel or user-defined alias, with an optional
+// distribution suffix) to the version and distribution to install. This is
+// where aliases are expanded for installs ("work" -> "17-amzn").
+func (m *Manager) ResolveInstallVersion(langName, version string) (string, string, error) {
+	lang, ok := languages.Get(langName)
+	if !ok {
+		return "", "", fmt.Errorf("unknown language: %s", langName)
+	}
+
+	target := m.ExpandAlias(langName, version)
+	base, dist := sources.ParseVersionAndDistribution(target)
+	resolved, err := lang.ResolveVersionWithDist(base, dist)
+	if err != nil {
+		if target != version {
+			return "", "", fmt.Errorf("resolving alias %s (%s): %w", version, target, err)
+		}
+		return "", "", err
+	}
+	return resolved, dist, nil
+}
+
+// InstallWithDist downloads and installs a version with a specific distribution.
+// The version is a concrete one, as returned by ResolveInstallVersion.
 func (m *Manager) InstallWithDist(langName, version, dist string) error {
 	lang, ok := languages.Get(langName)
 	if !ok {
@@ -311,6 +394,12 @@ func (m *Manager) InstallWithDist(langName, version, dist string) error {
 	if dist != "" {
 		versionKey = version + "-" + dist
 	}
+	l, err := m.lockLanguage(langName)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = l.Release() }()
+
 	versionPath := m.Config.GetVersionPath(langName, versionKey)
 	if _, err := os.Stat(versionPath); err == nil {
 		return fmt.Errorf("version %s already installed", versionKey)
@@ -327,39 +416,35 @@ func (m *Manager) InstallWithDist(langName, version, dist string) error {
 	}
 	fmt.Printf("Downloading %s %s from %s...\n", langName, displayVer, url)
 
-	// Create version directory
-	if err := os.MkdirAll(versionPath, 0755); err != nil {
-		return err
+	// Assemble the install in a staging directory; the version directory only
+	// appears once everything succeeded, so a failed or killed install never
+	// looks installed
+	stagingPath, err := m.newStagingDir(langName, versionKey)
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
 	}
+	defer func() { _ = os.RemoveAll(stagingPath) }()
 
 	// Check download type
 	downloadType := lang.GetDownloadType()
 
-	// Get checksum URL if available
-	checksumURL := lang.GetChecksumURL(version, dist)
-	var expectedChecksum string
-	if checksumURL != "" {
-		fmt.Printf("Fetching checksum...\n")
-		if cs, err := FetchChecksum(checksumURL); err == nil {
-			expectedChecksum = cs
-			fmt.Printf("Checksum: %s...\n", expectedChecksum[:min(16, len(expectedChecksum))])
-		}
-	}
+	expectedChecksum := expectedChecksum(lang, version, dist)
 
 	if downloadType == "file" {
-		// Single file download - save directly to version directory
+		// Single file download - save directly to the staging directory
 		fileName := filepath.Base(url)
-		destPath := filepath.Join(versionPath, fileName)
+		destPath := filepath.Join(stagingPath, fileName)
 
 		cfg := DefaultDownloadConfig()
 		cfg.URL = url
 		cfg.DestPath = destPath
 		cfg.Description = displayVer
-		cfg.ExpectedSHA256 = expectedChecksum
+		cfg.ExpectedChecksum = expectedChecksum
+		cfg.Cache = m.DownloadCache()
+		cfg.Offline = sources.Offline()
 
 		result, err := DownloadWithRetry(cfg)
 		if err != nil {
-			_ = os.RemoveAll(versionPath)
 			return fmt.Errorf("download failed: %w", err)
 		}
 
@@ -367,10 +452,11 @@ func (m *Manager) InstallWithDist(langName, version, dist string) error {
 			fmt.Printf("Download succeeded after %d retries\n", result.Retries)
 		}
 	} else {
-		// Zip archive download
-		tmpFile, err := os.CreateTemp("", "verman-*.zip")
+		// Archive download (zip, tar.gz, tar.xz, or auto-detected)
+		// Kept next to the staging directory so the sweep also clears
+		// downloads abandoned by a killed process
+		tmpFile, err := os.CreateTemp(m.stagingRoot(), fmt.Sprintf("%d-%s-%s-*.archive", os.Getpid(), langName, versionKey))
 		if err != nil {
-			_ = os.RemoveAll(versionPath)
 			return err
 		}
 		tmpPath := tmpFile.Name()
@@ -381,11 +467,12 @@ func (m *Manager) InstallWithDist(langName, version, dist string) error {
 		cfg.URL = url
 		cfg.DestPath = tmpPath
 		cfg.Description = displayVer
-		cfg.ExpectedSHA256 = expectedChecksum
+		cfg.ExpectedChecksum = expectedChecksum
+		cfg.Cache = m.DownloadCache()
+		cfg.Offline = sources.Offline()
 
 		result, err := DownloadWithRetry(cfg)
 		if err != nil {
-			_ = os.RemoveAll(versionPath)
 			return fmt.Errorf("download failed: %w", err)
 		}
 
@@ -397,20 +484,30 @@ func (m *Manager) InstallWithDist(langName, version, dist string) error {
 			fmt.Printf("Checksum verified: %s\n", result.SHA256[:16])
 		}
 
+		archiveType := downloadType
+		if archiveType == "auto" {
+			archiveType, err = DetectArchiveType(url, result.ContentType, tmpPath)
+			if err != nil {
+				return err
+			}
+		}
+
 		fmt.Printf("Extracting to %s...\n", versionPath)
 
-		// Extract zip
-		if err := extractZip(tmpPath, versionPath); err != nil {
-			_ = os.RemoveAll(versionPath)
+		if err := extractArchive(tmpPath, stagingPath, archiveType, m.extractLimits()); err != nil {
 			return fmt.Errorf("extraction failed: %w", err)
 		}
 	}
 
 	// Run post-install
-	if err := lang.PostInstall(versionPath); err != nil {
+	if err := lang.PostInstall(stagingPath); err != nil {
 		return fmt.Errorf("post-install failed: %w", err)
 	}
 
+	if err := commitStaging(stagingPath, versionPath); err != nil {
+		return fmt.Errorf("failed to move install into place: %w", err)
+	}
+
 	fmt.Printf("Successfully installed %s %s\n", langName, displayVer)
 
 	// For Java, offer to set JAVA_HOME globally
@@ -431,6 +528,93 @@ func (m *Manager) InstallWithDist(langName, version, dist string) error {
 	return nil
 }
 
+// expectedChecksum returns the checksum to verify a download against: from
+// release metadata or a Maven sidecar file (both may be fetched), or from the
+// source's checksum URL. Empty when none is published.
+func expectedChecksum(lang languages.Language, version, dist string) string {
+	checksum := lang.GetChecksum(version, dist)
+	checksumURL := lang.GetChecksumURL(version, dist)
+	if checksum == "" && checksumURL != "" && !sources.Offline() {
+		fmt.Printf("Fetching checksum...\n")
+		if cs, err := FetchChecksum(checksumURL); err == nil {
+			checksum = cs
+		}
+	}
+	if checksum != "" {
+		fmt.Printf("Checksum: %s...\n", checksum[:min(16, len(checksum))])
+	}
+	return checksum
+}
+
+// DownloadFor saves the download for a version in destDir without installing
+// it. It serves "verman install --platform" for another system, whose builds
+// cannot run here and so are never registered as installed. Returns the path
+// of the saved file.
+func (m *Manager) DownloadFor(langName, version, dist, destDir string) (string, error) {
+	lang, ok := languages.Get(langName)
+	if !ok {
+		return "", fmt.Errorf("unknown language: %s", langName)
+	}
+	if !lang.ValidateVersion(version) {
+		return "", fmt.Errorf("invalid version format: %s", version)
+	}
+
+	url, err := lang.GetDownloadURLWithDist(version, dist)
+	if err != nil {
+		return "", fmt.Errorf("failed to get download URL: %w", err)
+	}
+
+	displayVer := version
+	if dist != "" {
+		displayVer = version + "-" + dist
+	}
+	platform := sources.CurrentPlatform()
+	fmt.Printf("Downloading %s %s for %s from %s...\n", langName, displayVer, platform, url)
+
+	tmpFile, err := os.CreateTemp(destDir, ".verman-download-*")
+	if err != nil {
+		return "", err
+	}
+	tmpPath := tmpFile.Name()
+	_ = tmpFile.Close()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	cfg := DefaultDownloadConfig()
+	cfg.URL = url
+	cfg.DestPath = tmpPath
+	cfg.Description = displayVer
+	cfg.ExpectedChecksum = expectedChecksum(lang, version, dist)
+	cfg.Cache = m.DownloadCache()
+	cfg.Offline = sources.Offline()
+
+	result, err := DownloadWithRetry(cfg)
+	if err != nil {
+		return "", fmt.Errorf("download failed: %w", err)
+	}
+
+	// Name archives after what they hold; API URLs often have no file name
+	name := filepath.Base(url)
+	if downloadType := lang.GetDownloadType(); downloadType != "file" {
+		archiveType := downloadType
+		if archiveType == "auto" {
+			archiveType, err = DetectArchiveType(url, result.ContentType, tmpPath)
+			if err != nil {
+				return "", err
+			}
+		}
+		name = fmt.Sprintf("%s-%s-%s-%s.%s", langName, displayVer, platform.OS, platform.Arch, archiveType)
+	}
+
+	destPath := filepath.Join(destDir, name)
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmpPath, destPath); err != nil {
+		return "", err
+	}
+	return destPath, nil
+}
+
 // offerJavaHomeSetup prompts user to set JAVA_HOME globally after Java installation
 func (m *Manager) offerJavaHomeSetup(javaPath string) {
 	// Check if JAVA_HOME is already set
@@ -477,6 +661,12 @@ func (m *Manager) offerJavaHomeSetup(javaPath string) {
 
 // Uninstall removes an installed version
 func (m *Manager) Uninstall(langName, version string) error {
+	l, err := m.lockLanguage(langName)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = l.Release() }()
+
 	versionPath := m.Config.GetVersionPath(langName, version)
 	if _, err := os.Stat(versionPath); os.IsNotExist(err) {
 		return fmt.Errorf("version %s not installed", version)
@@ -490,69 +680,3 @@ func (m *Manager) Uninstall(langName, version string) error {
 
 	return os.RemoveAll(versionPath)
 }
-
-func extractZip(zipPath, destPath string) error {
-	r, err := zip.OpenReader(zipPath)
-	if err != nil {
-		return err
-	}
-	defer func() { _ = r.Close() }()
-
-	// Find common prefix (many zips have a single root folder)
-	var prefix string
-	if len(r.File) > 0 {
-		first := r.File[0].Name
-		if idx := strings.Index(first, "/"); idx > 0 {
-			prefix = first[:idx+1]
-			// Verify all files have this prefix
-			for _, f := range r.File {
-				if !strings.HasPrefix(f.Name, prefix) {
-					prefix = ""
-					break
-				}
-			}
-		}
-	}
-
-	for _, f := range r.File {
-		name := f.Name
-		if prefix != "" {
-			name = strings.TrimPrefix(name, prefix)
-		}
-		if name == "" {
-			continue
-		}
-
-		fpath := filepath.Join(destPath, name)
-
-		if f.FileInfo().IsDir() {
-			_ = os.MkdirAll(fpath, 0755)
-			continue
-		}
-
-		if err := os.MkdirAll(filepath.Dir(fpath), 0755); err != nil {
-			return err
-		}
-
-		outFile, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
-		if err != nil {
-			return err
-		}
-
-		rc, err := f.Open()
-		if err != nil {
-			_ = outFile.Close()
-			return err
-		}
-
-		_, err = io.Copy(outFile, rc)
-		_ = outFile.Close()
-		_ = rc.Close()
-
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
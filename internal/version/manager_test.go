@@ -1,13 +1,16 @@
 package version
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/azdren/verman/internal/config"
 	_ "github.com/azdren/verman/internal/languages" // Register languages
+	"github.com/azdren/verman/internal/lock"
 )
 
 // setupTestManager creates a test manager with a sandbox environment
@@ -17,6 +20,10 @@ func setupTestManager(t *testing.T) (*Manager, string) {
 	tmpDir := t.TempDir()
 	versionsDir := filepath.Join(tmpDir, ".verman", "versions")
 
+	// Keep shims created by Use out of the real home directory
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("USERPROFILE", tmpDir)
+
 	// Create directories for all languages
 	for _, lang := range []string{"java", "node", "scala", "python", "ruby", "go", "rust", "dotnet"} {
 		_ = os.MkdirAll(filepath.Join(versionsDir, lang), 0755)
@@ -113,7 +120,7 @@ func TestUseVersion(t *testing.T) {
 	createMockVersion(t, mgr, "java", "21")
 
 	// Use it
-	err := mgr.Use("java", "21", false)
+	_, err := mgr.Use("java", "21", false)
 	if err != nil {
 		t.Fatalf("Use failed: %v", err)
 	}
@@ -144,16 +151,47 @@ func TestUseVersion(t *testing.T) {
 func TestUseNonExistentVersion(t *testing.T) {
 	mgr, _ := setupTestManager(t)
 
-	err := mgr.Use("java", "99", false)
+	_, err := mgr.Use("java", "99", false)
 	if err == nil {
 		t.Error("Expected error when using non-existent version")
 	}
 }
 
+func TestResolveInstalled(t *testing.T) {
+	mgr, _ := setupTestManager(t)
+	for _, v := range []string{"17.0.9", "21.0.2", "21.0.5-amzn", "11"} {
+		createMockVersion(t, mgr, "java", v)
+	}
+
+	tests := map[string]string{
+		">=17 <22":   "21.0.5-amzn",
+		"^17":        "17.0.9",
+		"~21.0-tem":  "21.0.2",
+		"^21-amzn":   "21.0.5-amzn",
+		"21.0.2":     "21.0.2", // Not a range: returned as given
+		"latest":     "21.0.5-amzn",
+		"latest-tem": "21.0.2",
+	}
+	for spec, want := range tests {
+		got, err := mgr.ResolveInstalled("java", spec)
+		if err != nil {
+			t.Errorf("ResolveInstalled(%q): %v", spec, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ResolveInstalled(%q) = %s, want %s", spec, got, want)
+		}
+	}
+
+	if _, err := mgr.ResolveInstalled("java", "^22"); err == nil {
+		t.Error("Expected error when no installed version satisfies the range")
+	}
+}
+
 func TestUseUnknownLanguage(t *testing.T) {
 	mgr, _ := setupTestManager(t)
 
-	err := mgr.Use("cobol", "1.0", false)
+	_, err := mgr.Use("cobol", "1.0", false)
 	if err == nil {
 		t.Error("Expected error for unknown language")
 	}
@@ -217,7 +255,7 @@ func TestSwitchBetweenVersions(t *testing.T) {
 	createMockVersion(t, mgr, "java", "21")
 
 	// Use version 17
-	if err := mgr.Use("java", "17", false); err != nil {
+	if _, err := mgr.Use("java", "17", false); err != nil {
 		t.Fatalf("Failed to use java 17: %v", err)
 	}
 
@@ -227,7 +265,7 @@ func TestSwitchBetweenVersions(t *testing.T) {
 	}
 
 	// Switch to version 21
-	if err := mgr.Use("java", "21", false); err != nil {
+	if _, err := mgr.Use("java", "21", false); err != nil {
 		t.Fatalf("Failed to use java 21: %v", err)
 	}
 
@@ -250,9 +288,9 @@ func TestMultipleLanguages(t *testing.T) {
 	createMockVersion(t, mgr, "scala", "2.13.12")
 
 	// Use each
-	_ = mgr.Use("java", "21", false)
-	_ = mgr.Use("node", "20", false)
-	_ = mgr.Use("scala", "2.13.12", false)
+	_, _ = mgr.Use("java", "21", false)
+	_, _ = mgr.Use("node", "20", false)
+	_, _ = mgr.Use("scala", "2.13.12", false)
 
 	// Verify each has correct current
 	tests := []struct {
@@ -271,3 +309,34 @@ func TestMultipleLanguages(t *testing.T) {
 		}
 	}
 }
+
+func TestUseWaitsForLanguageLock(t *testing.T) {
+	mgr, _ := setupTestManager(t)
+	createMockVersion(t, mgr, "node", "20.0.0")
+
+	defer lock.SetTimeout(lock.Timeout())
+	lock.SetTimeout(100 * time.Millisecond)
+
+	held, err := lock.Acquire(mgr.Config.LockPath("node"))
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	var timeoutErr *lock.TimeoutError
+	if _, err := mgr.Use("node", "20.0.0", false); !errors.As(err, &timeoutErr) {
+		t.Fatalf("Expected lock timeout while another process holds the lock, got %v", err)
+	}
+
+	// Other languages are not blocked
+	createMockVersion(t, mgr, "java", "21")
+	if _, err := mgr.Use("java", "21", false); err != nil {
+		t.Errorf("Use java should not wait on the node lock: %v", err)
+	}
+
+	_ = held.Release()
+	if ver, err := mgr.Use("node", "^20", false); err != nil {
+		t.Errorf("Use failed after lock release: %v", err)
+	} else if ver != "20.0.0" {
+		t.Errorf("Use returned %s, want the resolved version 20.0.0", ver)
+	}
+}
@@ -0,0 +1,171 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Markers around the managed block in a shell profile. verman owns the
+// lines between them and rewrites them in place, so setting a variable
+// again replaces its line instead of adding another.
+const (
+	profileBlockStart = "# >>> verman >>>"
+	profileBlockEnd   = "# <<< verman <<<"
+)
+
+// markerLine returns the offset of the first line of content that is
+// marker, ignoring surrounding whitespace, and the offset just past that
+// line; -1, -1 if no line is
+func markerLine(content, marker string) (int, int) {
+	for offset := 0; offset < len(content); {
+		line, next := content[offset:], len(content)
+		if nl := strings.IndexByte(line, '\n'); nl >= 0 {
+			line, next = line[:nl], offset+nl+1
+		}
+		if strings.TrimSpace(line) == marker {
+			return offset, next
+		}
+		offset = next
+	}
+	return -1, -1
+}
+
+// profileBlock returns the lines inside the managed block of content, none
+// if the block has no end marker
+func profileBlock(content string) []string {
+	_, start := markerLine(content, profileBlockStart)
+	if start < 0 {
+		return nil
+	}
+	inner := content[start:]
+	end, _ := markerLine(inner, profileBlockEnd)
+	if end <= 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(inner[:end], "\n"), "\n")
+}
+
+// setProfileBlock returns content with the managed block holding lines:
+// replaced where it was, or appended after a blank line when there is none.
+// No lines removes the block and that blank line.
+func setProfileBlock(content string, lines []string) string {
+	var block string
+	if len(lines) > 0 {
+		block = profileBlockStart + "\n" + strings.Join(lines, "\n") + "\n" + profileBlockEnd + "\n"
+	}
+
+	start, startEnd := markerLine(content, profileBlockStart)
+	if start < 0 {
+		if block == "" {
+			return content
+		}
+		if content != "" {
+			if !strings.HasSuffix(content, "\n") {
+				content += "\n"
+			}
+			content += "\n"
+		}
+		return content + block
+	}
+
+	// Without an end marker, everything past the start line is kept
+	before, after := content[:start], content[startEnd:]
+	if end, endEnd := markerLine(after, profileBlockEnd); end >= 0 {
+		after = after[endEnd:]
+	}
+	if block == "" && strings.HasSuffix(before, "\n\n") {
+		before = before[:len(before)-1] // The blank line in front of the block
+	}
+	return before + block + after
+}
+
+// setBlockVar returns block lines with name exported as value, replacing
+// the line that exported it before
+func setBlockVar(lines []string, name, value string) []string {
+	prefix := "export " + name + "="
+	line := ShellBash.SetVar(name, value)
+	for i, l := range lines {
+		if strings.HasPrefix(l, prefix) {
+			lines[i] = line
+			return lines
+		}
+	}
+	return append(lines, line)
+}
+
+// blockVar returns the value the block lines export for name
+func blockVar(lines []string, name string) string {
+	prefix := "export " + name + "="
+	for _, l := range lines {
+		if value, ok := strings.CutPrefix(l, prefix); ok {
+			// Undo ShellBash.Quote
+			value = strings.TrimSuffix(strings.TrimPrefix(value, "'"), "'")
+			return strings.ReplaceAll(value, `'\''`, "'")
+		}
+	}
+	return ""
+}
+
+// setProfileVar exports a variable from the managed block of a profile,
+// creating the file if needed
+func setProfileVar(path, name, value string) error {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	lines := setBlockVar(profileBlock(string(content)), name, value)
+	return writeProfile(path, setProfileBlock(string(content), lines))
+}
+
+// removeProfileBlock removes the managed block from a profile and reports
+// whether there was one
+func removeProfileBlock(path string) (bool, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if start, _ := markerLine(string(content), profileBlockStart); start < 0 {
+		return false, nil
+	}
+	return true, writeProfile(path, setProfileBlock(string(content), nil))
+}
+
+// writeProfile replaces a profile through a temp file in the same
+// directory, so an interrupted write leaves the old profile intact. A
+// symlinked profile (dotfile managers) is written where the link points,
+// and the file keeps its permissions.
+func writeProfile(path, content string) error {
+	mode := os.FileMode(0644)
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+		if info, err := os.Stat(path); err == nil {
+			mode = info.Mode().Perm()
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".verman-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	_, err = tmp.WriteString(content)
+	if err == nil {
+		err = tmp.Chmod(mode)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
@@ -0,0 +1,157 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/azdren/verman/internal/languages"
+)
+
+func TestSetProfileBlock(t *testing.T) {
+	block := profileBlockStart + "\nexport A='1'\n" + profileBlockEnd + "\n"
+
+	tests := []struct {
+		name    string
+		content string
+		lines   []string
+		want    string
+	}{
+		{"empty file", "", []string{"export A='1'"}, block},
+		{"appended after a blank line", "alias ll='ls -l'", []string{"export A='1'"}, "alias ll='ls -l'\n\n" + block},
+		{"replaced in place", "x\n\n" + profileBlockStart + "\nexport A='0'\n" + profileBlockEnd + "\ny\n",
+			[]string{"export A='1'"}, "x\n\n" + block + "y\n"},
+		{"removed", "x\n\n" + block, nil, "x\n"},
+		{"removed between lines", "x\n\n" + block + "y\n", nil, "x\ny\n"},
+		{"nothing to remove", "x\n", nil, "x\n"},
+		{"missing end marker keeps the rest", "x\n" + profileBlockStart + "\ny\n", []string{"export A='1'"}, "x\n" + block + "y\n"},
+		{"markers inside other lines are not the block", "echo '" + profileBlockStart + "'\n# " + profileBlockEnd + " here\n",
+			[]string{"export A='1'"}, "echo '" + profileBlockStart + "'\n# " + profileBlockEnd + " here\n\n" + block},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := setProfileBlock(tt.content, tt.lines); got != tt.want {
+				t.Errorf("setProfileBlock() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetProfileVar(t *testing.T) {
+	profilePath := filepath.Join(t.TempDir(), ".bashrc")
+	if err := os.WriteFile(profilePath, []byte("# my settings\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Setting a variable again rewrites its line instead of adding another
+	for _, value := range []string{"/old/jdk", "/opt/it's/jdk", "/opt/it's/jdk"} {
+		if err := setProfileVar(profilePath, "JAVA_HOME", value); err != nil {
+			t.Fatalf("setProfileVar failed: %v", err)
+		}
+	}
+	if err := setProfileVar(profilePath, "GOROOT", "/opt/go"); err != nil {
+		t.Fatalf("setProfileVar failed: %v", err)
+	}
+
+	content, _ := os.ReadFile(profilePath)
+	want := "# my settings\n\n" + profileBlockStart + "\nexport JAVA_HOME='/opt/it'\\''s/jdk'\nexport GOROOT='/opt/go'\n" + profileBlockEnd + "\n"
+	if string(content) != want {
+		t.Errorf("profile = %q, want %q", content, want)
+	}
+	if got := blockVar(profileBlock(string(content)), "JAVA_HOME"); got != "/opt/it's/jdk" {
+		t.Errorf("blockVar(JAVA_HOME) = %q, want %q", got, "/opt/it's/jdk")
+	}
+
+	removed, err := removeProfileBlock(profilePath)
+	if err != nil || !removed {
+		t.Fatalf("removeProfileBlock = %v, %v; want true, nil", removed, err)
+	}
+	content, _ = os.ReadFile(profilePath)
+	if string(content) != "# my settings\n" {
+		t.Errorf("profile after removal = %q", content)
+	}
+	if removed, _ := removeProfileBlock(profilePath); removed {
+		t.Error("removeProfileBlock should report no block the second time")
+	}
+}
+
+func TestSetProfileVarSymlinkedProfile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Profiles are Unix only")
+	}
+	dir := t.TempDir()
+	target := filepath.Join(dir, "dotfiles", "bashrc")
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte("# mine\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	profilePath := filepath.Join(dir, ".bashrc")
+	if err := os.Symlink(target, profilePath); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := setProfileVar(profilePath, "GOROOT", "/opt/go"); err != nil {
+		t.Fatalf("setProfileVar failed: %v", err)
+	}
+
+	if info, err := os.Lstat(profilePath); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("profile is no longer a symlink: %v", err)
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("profile mode = %v, want 0600", info.Mode().Perm())
+	}
+	content, _ := os.ReadFile(target)
+	if got := blockVar(profileBlock(string(content)), "GOROOT"); got != "/opt/go" {
+		t.Errorf("blockVar(GOROOT) = %q, want /opt/go", got)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(target)); len(entries) != 1 {
+		t.Errorf("temp files left next to the profile: %v", entries)
+	}
+}
+
+func TestSetGlobalEnvUnix(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows keeps global variables in the registry")
+	}
+	mgr, home := setupTestManager(t)
+	t.Setenv("SHELL", "/bin/bash")
+	t.Setenv("PATH", "") // No fish to remove universal variables from
+
+	java, _ := languages.Get("java")
+	currentPath := mgr.Config.GetCurrentPath("java")
+	for i := 0; i < 2; i++ {
+		if err := mgr.SetGlobalEnv(java, currentPath); err != nil {
+			t.Fatalf("SetGlobalEnv failed: %v", err)
+		}
+	}
+
+	bashrc := filepath.Join(home, ".bashrc")
+	content, _ := os.ReadFile(bashrc)
+	if n := strings.Count(string(content), "export JAVA_HOME="); n != 1 {
+		t.Errorf("~/.bashrc exports JAVA_HOME %d times, want 1:\n%s", n, content)
+	}
+	if got, _ := getUserEnvVar("JAVA_HOME"); got != currentPath {
+		t.Errorf("getUserEnvVar(JAVA_HOME) = %q, want %q", got, currentPath)
+	}
+
+	removed, err := mgr.RemoveGlobalEnv()
+	if err != nil {
+		t.Fatalf("RemoveGlobalEnv failed: %v", err)
+	}
+	if len(removed) != 1 || removed[0] != bashrc {
+		t.Errorf("RemoveGlobalEnv removed from %v, want [%s]", removed, bashrc)
+	}
+	content, _ = os.ReadFile(bashrc)
+	if strings.Contains(string(content), "verman") {
+		t.Errorf("~/.bashrc still has the managed block:\n%s", content)
+	}
+}
@@ -75,15 +75,15 @@ func (pw *ProgressWriter) display() {
 		fmt.Printf("\r[%s] %5.1f%% %s/%s %s/s ETA %s   ",
 			bar,
 			percent,
-			formatBytes(pw.current),
-			formatBytes(pw.total),
-			formatBytes(int64(speed)),
+			FormatBytes(pw.current),
+			FormatBytes(pw.total),
+			FormatBytes(int64(speed)),
 			formatDuration(eta))
 	} else {
 		// Unknown total size - show downloaded amount and speed
 		fmt.Printf("\r%s downloaded %s/s   ",
-			formatBytes(pw.current),
-			formatBytes(int64(speed)))
+			FormatBytes(pw.current),
+			FormatBytes(int64(speed)))
 	}
 }
 
@@ -98,13 +98,13 @@ func (pw *ProgressWriter) Finish() {
 	// Clear the progress line and print completion message
 	fmt.Printf("\r%s\r", strings.Repeat(" ", 80))
 	fmt.Printf("Downloaded %s in %s (%s/s)\n",
-		formatBytes(pw.current),
+		FormatBytes(pw.current),
 		formatDuration(elapsed),
-		formatBytes(int64(speed)))
+		FormatBytes(int64(speed)))
 }
 
-// formatBytes formats bytes into human-readable format
-func formatBytes(b int64) string {
+// FormatBytes formats bytes into human-readable format
+func FormatBytes(b int64) string {
 	const unit = 1024
 	if b < unit {
 		return fmt.Sprintf("%d B", b)
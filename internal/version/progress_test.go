@@ -22,9 +22,9 @@ func TestFormatBytes(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		result := formatBytes(tt.bytes)
+		result := FormatBytes(tt.bytes)
 		if result != tt.expected {
-			t.Errorf("formatBytes(%d): expected %q, got %q", tt.bytes, tt.expected, result)
+			t.Errorf("FormatBytes(%d): expected %q, got %q", tt.bytes, tt.expected, result)
 		}
 	}
 }
@@ -0,0 +1,97 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/azdren/verman/internal/languages"
+)
+
+// Where a selected version came from, highest precedence first
+const (
+	OriginSession = "session" // VERMAN_<LANG>_VERSION set by "verman shell"
+	OriginProject = "project" // nearest version file
+	OriginGlobal  = "global"  // the "current" link written by "verman use"
+)
+
+// Selection is the version in effect for a language in a directory
+type Selection struct {
+	Language string
+	Version  string // Installed version folder name
+	Origin   string // OriginSession, OriginProject or OriginGlobal
+	Source   string // Variable or version file that selected it
+	Path     string // Version root to run from
+}
+
+// NotInstalledError reports a session or project version that is not installed
+type NotInstalledError struct {
+	Language string
+	Version  string
+	Source   string
+}
+
+func (e *NotInstalledError) Error() string {
+	return fmt.Sprintf("%s %s is not installed (set by %s); run: verman install %s %s",
+		e.Language, e.Version, e.Source, e.Language, e.Version)
+}
+
+// SessionVar returns the variable holding a language's session override,
+// e.g. VERMAN_JAVA_VERSION
+func SessionVar(langName string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, langName)
+	return "VERMAN_" + name + "_VERSION"
+}
+
+// Select resolves the version in effect for a language in dir: a session
+// override wins over the nearest project version file, which wins over the
+// global version. It returns nil when none is set.
+func (m *Manager) Select(langName, dir string) (*Selection, error) {
+	lang, ok := languages.Get(langName)
+	if !ok {
+		return nil, fmt.Errorf("unknown language: %s", langName)
+	}
+
+	if v := os.Getenv(SessionVar(langName)); v != "" {
+		return m.selectInstalled(langName, v, OriginSession, SessionVar(langName))
+	}
+
+	aliases := func(langName, version string) (string, bool) {
+		return m.Config.Alias(langName, version)
+	}
+	if dv := detectForLanguage(dir, lang, aliases); dv != nil {
+		return m.selectInstalled(langName, dv.Version, OriginProject, dv.Source)
+	}
+
+	current, err := m.GetCurrent(langName)
+	if err != nil || current == "" {
+		return nil, err
+	}
+	return &Selection{
+		Language: langName,
+		Version:  current,
+		Origin:   OriginGlobal,
+		Source:   m.Config.GetCurrentPath(langName),
+		Path:     m.Config.GetCurrentPath(langName),
+	}, nil
+}
+
+func (m *Manager) selectInstalled(langName, version, origin, source string) (*Selection, error) {
+	resolved, err := m.resolveExecVersion(langName, version)
+	if err != nil {
+		return nil, &NotInstalledError{Language: langName, Version: version, Source: source}
+	}
+	return &Selection{
+		Language: langName,
+		Version:  resolved,
+		Origin:   origin,
+		Source:   source,
+		Path:     m.Config.GetVersionPath(langName, resolved),
+	}, nil
+}
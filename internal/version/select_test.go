@@ -0,0 +1,136 @@
+package version
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSessionVar(t *testing.T) {
+	tests := map[string]string{
+		"java":   "VERMAN_JAVA_VERSION",
+		"scala3": "VERMAN_SCALA3_VERSION",
+		"sbt-x":  "VERMAN_SBT_X_VERSION",
+	}
+	for lang, want := range tests {
+		if got := SessionVar(lang); got != want {
+			t.Errorf("SessionVar(%q) = %s, want %s", lang, got, want)
+		}
+	}
+}
+
+func TestSelectPrecedence(t *testing.T) {
+	mgr, tmpDir := setupTestManager(t)
+	java17 := createMockVersion(t, mgr, "java", "17.0.9")
+	java21 := createMockVersion(t, mgr, "java", "21.0.2")
+	java11 := createMockVersion(t, mgr, "java", "11.0.21")
+	if err := switchJunction(mgr.Config.GetCurrentPath("java"), java21); err != nil {
+		t.Fatalf("switchJunction failed: %v", err)
+	}
+
+	project := filepath.Join(tmpDir, "project")
+	_ = os.MkdirAll(filepath.Join(project, "src"), 0755)
+	_ = os.WriteFile(filepath.Join(project, ".java-version"), []byte("17\n"), 0644)
+	t.Setenv(SessionVar("java"), "")
+
+	sel, err := mgr.Select("java", tmpDir)
+	if err != nil || sel == nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if sel.Origin != OriginGlobal || sel.Version != "21.0.2" || sel.Path != mgr.Config.GetCurrentPath("java") {
+		t.Errorf("Outside the project got %+v, want global 21.0.2", sel)
+	}
+
+	sel, err = mgr.Select("java", filepath.Join(project, "src"))
+	if err != nil || sel == nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if sel.Origin != OriginProject || sel.Version != "17.0.9" || sel.Path != java17 {
+		t.Errorf("In the project got %+v, want project 17.0.9", sel)
+	}
+
+	t.Setenv(SessionVar("java"), "11")
+	sel, err = mgr.Select("java", project)
+	if err != nil || sel == nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if sel.Origin != OriginSession || sel.Version != "11.0.21" || sel.Path != java11 {
+		t.Errorf("With a session version got %+v, want session 11.0.21", sel)
+	}
+
+	if sel, err := mgr.Select("node", tmpDir); err != nil || sel != nil {
+		t.Errorf("Select(node) = %+v, %v; want nothing set", sel, err)
+	}
+}
+
+func TestSelectNotInstalled(t *testing.T) {
+	mgr, _ := setupTestManager(t)
+	createMockVersion(t, mgr, "java", "17.0.9")
+	t.Setenv(SessionVar("java"), "8")
+
+	_, err := mgr.Select("java", t.TempDir())
+	var notInstalled *NotInstalledError
+	if !errors.As(err, &notInstalled) {
+		t.Fatalf("Expected NotInstalledError, got %v", err)
+	}
+	if notInstalled.Source != SessionVar("java") {
+		t.Errorf("Source = %s, want %s", notInstalled.Source, SessionVar("java"))
+	}
+}
+
+func TestSessionEnv(t *testing.T) {
+	mgr, _ := setupTestManager(t)
+	java17 := createMockVersion(t, mgr, "java", "17.0.9")
+	java21 := createMockVersion(t, mgr, "java", "21.0.2")
+	current := mgr.Config.GetCurrentPath("java")
+	if err := switchJunction(current, java21); err != nil {
+		t.Fatalf("switchJunction failed: %v", err)
+	}
+
+	sep := string(os.PathListSeparator)
+	environ := []string{"PATH=" + filepath.Join(current, "bin") + sep + "/usr/bin"}
+
+	exports, err := mgr.SessionEnv("java", "17", environ)
+	if err != nil {
+		t.Fatalf("SessionEnv failed: %v", err)
+	}
+	got := make(map[string]string)
+	for _, e := range exports {
+		got[e.Name] = e.Value
+	}
+	if got[SessionVar("java")] != "17.0.9" {
+		t.Errorf("%s = %q, want 17.0.9", SessionVar("java"), got[SessionVar("java")])
+	}
+	if got["JAVA_HOME"] != java17 {
+		t.Errorf("JAVA_HOME = %q, want %s", got["JAVA_HOME"], java17)
+	}
+	wantPath := filepath.Join(java17, "bin") + sep + "/usr/bin"
+	if got["PATH"] != wantPath {
+		t.Errorf("PATH = %q, want %q", got["PATH"], wantPath)
+	}
+
+	// Unsetting swaps the session directories back for the global ones
+	exports, err = mgr.SessionEnv("java", "", []string{"PATH=" + wantPath})
+	if err != nil {
+		t.Fatalf("SessionEnv unset failed: %v", err)
+	}
+	got = make(map[string]string)
+	for _, e := range exports {
+		got[e.Name] = e.Value
+	}
+	if v, ok := got[SessionVar("java")]; !ok || v != "" {
+		t.Errorf("Session variable should be cleared, got %q", v)
+	}
+	if got["JAVA_HOME"] != current {
+		t.Errorf("JAVA_HOME = %q, want %s", got["JAVA_HOME"], current)
+	}
+	if !strings.HasPrefix(got["PATH"], filepath.Join(current, "bin")+sep) || strings.Contains(got["PATH"], java17) {
+		t.Errorf("PATH = %q, want global bin first and no session bin", got["PATH"])
+	}
+
+	if _, err := mgr.SessionEnv("java", "8", environ); err == nil {
+		t.Error("Expected error for a version that is not installed")
+	}
+}
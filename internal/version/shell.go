@@ -0,0 +1,205 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Shell is a shell dialect verman generates code for
+type Shell string
+
+const (
+	ShellBash   Shell = "bash"
+	ShellZsh    Shell = "zsh"
+	ShellFish   Shell = "fish"
+	ShellPwsh   Shell = "pwsh"
+	ShellCmd    Shell = "cmd"
+	ShellNu     Shell = "nu"
+	ShellElvish Shell = "elvish"
+)
+
+// ParseShell parses a shell name ("powershell" is accepted for pwsh)
+func ParseShell(name string) (Shell, error) {
+	switch strings.ToLower(name) {
+	case "bash", "sh":
+		return ShellBash, nil
+	case "zsh":
+		return ShellZsh, nil
+	case "fish":
+		return ShellFish, nil
+	case "pwsh", "powershell":
+		return ShellPwsh, nil
+	case "cmd":
+		return ShellCmd, nil
+	case "nu", "nushell":
+		return ShellNu, nil
+	case "elvish":
+		return ShellElvish, nil
+	}
+	return "", fmt.Errorf("unknown shell %q (supported: bash, zsh, fish, pwsh, cmd, nu, elvish)", name)
+}
+
+// DetectShell guesses the calling shell: $SHELL on Unix, PowerShell on Windows
+func DetectShell() Shell {
+	if runtime.GOOS == "windows" {
+		return ShellPwsh
+	}
+	if sh, err := ParseShell(filepath.Base(os.Getenv("SHELL"))); err == nil {
+		return sh
+	}
+	return ShellBash
+}
+
+// Quote quotes a value for the shell
+func (sh Shell) Quote(s string) string {
+	switch sh {
+	case ShellFish:
+		return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+	case ShellPwsh:
+		// PowerShell also ends single-quoted strings at typographic quotes
+		return "'" + strings.NewReplacer("'", "''", "\u2018", "\u2018\u2018", "\u2019", "\u2019\u2019",
+			"\u201a", "\u201a\u201a", "\u201b", "\u201b\u201b").Replace(s) + "'"
+	case ShellElvish:
+		return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	case ShellNu:
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+	case ShellCmd:
+		// Used inside set "NAME=value", which protects & | < > ^; the
+		// statements are meant for a batch file, where %% is a literal %
+		return strings.ReplaceAll(s, "%", "%%")
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// quoteList quotes each directory of a PATH value, space separated
+func (sh Shell) quoteList(value string) string {
+	var parts []string
+	for _, dir := range filepath.SplitList(value) {
+		parts = append(parts, sh.Quote(dir))
+	}
+	return strings.Join(parts, " ")
+}
+
+// SetVar returns a statement exporting a variable. Shells that keep PATH as
+// a list (fish, nu, elvish) get it as one.
+func (sh Shell) SetVar(name, value string) string {
+	switch sh {
+	case ShellFish:
+		if name == "PATH" {
+			return "set -gx PATH " + sh.quoteList(value)
+		}
+		return fmt.Sprintf("set -gx %s %s", name, sh.Quote(value))
+	case ShellNu:
+		if name == "PATH" {
+			return fmt.Sprintf("$env.PATH = [%s]", sh.quoteList(value))
+		}
+		return fmt.Sprintf("$env.%s = %s", name, sh.Quote(value))
+	case ShellElvish:
+		if name == "PATH" {
+			return fmt.Sprintf("set paths = [%s]", sh.quoteList(value))
+		}
+		return fmt.Sprintf("set-env %s %s", name, sh.Quote(value))
+	case ShellPwsh:
+		return fmt.Sprintf("$env:%s = %s", name, sh.Quote(value))
+	case ShellCmd:
+		return fmt.Sprintf(`set "%s=%s"`, name, sh.Quote(value))
+	}
+	return fmt.Sprintf("export %s=%s", name, sh.Quote(value))
+}
+
+// UnsetVar returns a statement removing a variable
+func (sh Shell) UnsetVar(name string) string {
+	switch sh {
+	case ShellFish:
+		return "set -e " + name
+	case ShellNu:
+		return "hide-env -i " + name
+	case ShellElvish:
+		return "unset-env " + name
+	case ShellPwsh:
+		return fmt.Sprintf("Remove-Item Env:%s -ErrorAction SilentlyContinue", name)
+	case ShellCmd:
+		return fmt.Sprintf("set %s=", name)
+	}
+	return "unset " + name
+}
+
+// PrependPath returns a statement putting dir first on PATH unless it is
+// already there
+func (sh Shell) PrependPath(dir string) string {
+	q := sh.Quote(dir)
+	switch sh {
+	case ShellFish:
+		return fmt.Sprintf("contains -- %s $PATH; or set -gx PATH %s $PATH", q, q)
+	case ShellNu:
+		return "path add " + q // std's path add skips duplicates
+	case ShellElvish:
+		return fmt.Sprintf("set paths = [%s (each {|p| if (not-eq $p %s) { put $p } } $paths)]", q, q)
+	case ShellPwsh:
+		return fmt.Sprintf("if (($env:PATH -split [IO.Path]::PathSeparator) -notcontains %s) { $env:PATH = %s + [IO.Path]::PathSeparator + $env:PATH }", q, q)
+	case ShellCmd:
+		return fmt.Sprintf(`set "PATH=%s;%%PATH%%"`, q)
+	}
+	return fmt.Sprintf("case \":$PATH:\" in\n  *:%s:*) ;;\n  *) export PATH=%s:\"$PATH\" ;;\nesac", q, q)
+}
+
+// Comment returns a comment line
+func (sh Shell) Comment(text string) string {
+	if sh == ShellCmd {
+		return "REM " + text
+	}
+	return "# " + text
+}
+
+// Statements renders exports, unsetting those with an empty value. Nushell
+// sets them with one load-env record.
+func (sh Shell) Statements(exports []EnvExport) string {
+	var sb strings.Builder
+	if sh == ShellNu {
+		var set []EnvExport
+		for _, e := range exports {
+			if e.Value == "" {
+				sb.WriteString(sh.UnsetVar(e.Name) + "\n")
+			} else {
+				set = append(set, e)
+			}
+		}
+		if len(set) > 0 {
+			sb.WriteString("load-env " + sh.Output(set))
+		}
+		return sb.String()
+	}
+	for _, e := range exports {
+		if e.Value == "" {
+			sb.WriteString(sh.UnsetVar(e.Name))
+		} else {
+			sb.WriteString(sh.SetVar(e.Name, e.Value))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// Output renders exports the way commands print them for the shell to load.
+// Nushell cannot evaluate generated code, so it gets a record for
+// "from nuon | load-env" (PATH as a list, null for removed variables);
+// other shells get statements to eval.
+func (sh Shell) Output(exports []EnvExport) string {
+	if sh != ShellNu {
+		return sh.Statements(exports)
+	}
+	var fields []string
+	for _, e := range exports {
+		value := "null"
+		if e.Name == "PATH" {
+			value = "[" + sh.quoteList(e.Value) + "]"
+		} else if e.Value != "" {
+			value = sh.Quote(e.Value)
+		}
+		fields = append(fields, fmt.Sprintf("%s: %s", e.Name, value))
+	}
+	return "{" + strings.Join(fields, ", ") + "}\n"
+}
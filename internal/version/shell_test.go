@@ -0,0 +1,72 @@
+package version
+
+import (
+	"os"
+	"testing"
+)
+
+func TestShellStatements(t *testing.T) {
+	exports := []EnvExport{
+		{Name: "JAVA_HOME", Value: "/opt/it's here"},
+		{Name: "VERMAN_NODE_VERSION", Value: ""},
+	}
+	tests := map[Shell]string{
+		ShellBash:   "export JAVA_HOME='/opt/it'\\''s here'\nunset VERMAN_NODE_VERSION\n",
+		ShellFish:   "set -gx JAVA_HOME '/opt/it\\'s here'\nset -e VERMAN_NODE_VERSION\n",
+		ShellPwsh:   "$env:JAVA_HOME = '/opt/it''s here'\nRemove-Item Env:VERMAN_NODE_VERSION -ErrorAction SilentlyContinue\n",
+		ShellCmd:    "set \"JAVA_HOME=/opt/it's here\"\nset VERMAN_NODE_VERSION=\n",
+		ShellNu:     "hide-env -i VERMAN_NODE_VERSION\nload-env {JAVA_HOME: \"/opt/it's here\"}\n",
+		ShellElvish: "set-env JAVA_HOME '/opt/it''s here'\nunset-env VERMAN_NODE_VERSION\n",
+	}
+	for sh, want := range tests {
+		if got := sh.Statements(exports); got != want {
+			t.Errorf("%s:\ngot  %q\nwant %q", sh, got, want)
+		}
+	}
+}
+
+func TestParseShell(t *testing.T) {
+	for name, want := range map[string]Shell{"bash": ShellBash, "ZSH": ShellZsh, "powershell": ShellPwsh, "cmd": ShellCmd} {
+		if got, err := ParseShell(name); err != nil || got != want {
+			t.Errorf("ParseShell(%q) = %s, %v; want %s", name, got, err, want)
+		}
+	}
+	if _, err := ParseShell("tcsh"); err == nil {
+		t.Error("Expected error for an unsupported shell")
+	}
+}
+
+func TestShellOutput(t *testing.T) {
+	exports := []EnvExport{
+		{Name: "JAVA_HOME", Value: `C:\jdk "17"`},
+		{Name: "VERMAN_JAVA_VERSION", Value: ""},
+		{Name: "PATH", Value: "/a" + string(os.PathListSeparator) + "/b"},
+	}
+	want := `{JAVA_HOME: "C:\\jdk \"17\"", VERMAN_JAVA_VERSION: null, PATH: ["/a" "/b"]}` + "\n"
+	if got := ShellNu.Output(exports); got != want {
+		t.Errorf("nu output:\ngot  %q\nwant %q", got, want)
+	}
+	if got := ShellBash.Output(exports); got != ShellBash.Statements(exports) {
+		t.Errorf("bash output should be statements, got %q", got)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		sh    Shell
+		value string
+		want  string
+	}{
+		{ShellBash, `$HOME "x"`, `'$HOME "x"'`},
+		{ShellPwsh, "it\u2019s", "'it\u2019\u2019s'"},
+		{ShellPwsh, "$env:X", "'$env:X'"},
+		{ShellCmd, `C:\100%\bin`, `C:\100%%\bin`},
+		{ShellFish, `a\'b`, `'a\\\'b'`},
+		{ShellElvish, "it's", "'it''s'"},
+	}
+	for _, tt := range tests {
+		if got := tt.sh.Quote(tt.value); got != tt.want {
+			t.Errorf("%s.Quote(%q) = %q, want %q", tt.sh, tt.value, got, tt.want)
+		}
+	}
+}
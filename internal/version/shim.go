@@ -0,0 +1,232 @@
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/azdren/verman/internal/languages"
+	"github.com/azdren/verman/internal/lock"
+	"github.com/azdren/verman/internal/semver"
+)
+
+// Shims are links to the verman executable named after a tool ("java",
+// "mvn") in ~/.verman/bin. Run under a tool's name, verman resolves the
+// version in effect for the working directory (see Select) and runs the
+// real tool from it, so changing directory switches versions without a
+// shell hook. shims.json records which language provides each tool.
+
+// ShimDir returns the directory holding the shims and the verman executable
+func ShimDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".verman", "bin"), nil
+}
+
+// shimIndexPath returns the file mapping shim names to languages
+func shimIndexPath() (string, error) {
+	dir, err := ShimDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(dir), "shims.json"), nil
+}
+
+func loadShimIndex() (map[string]string, error) {
+	path, err := shimIndexPath()
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]string)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return index, nil
+}
+
+func saveShimIndex(index map[string]string) error {
+	path, err := shimIndexPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpPath := fmt.Sprintf("%s.%d.tmp", path, os.Getpid())
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// ShimLanguage returns the language providing a shimmed tool
+func ShimLanguage(name string) (string, bool) {
+	index, err := loadShimIndex()
+	if err != nil {
+		return "", false
+	}
+	langName, ok := index[shimKey(name)]
+	return langName, ok
+}
+
+// CreateShims links a shim into ~/.verman/bin for every executable in a
+// version's PATH directories and records the language providing it. When
+// two languages ship a tool of the same name, the last one switched to wins.
+func (m *Manager) CreateShims(langName, versionPath string, pathDirs []string) error {
+	shimDir, err := ShimDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(shimDir, 0755); err != nil {
+		return err
+	}
+
+	source, err := Executable()
+	if err != nil {
+		return err
+	}
+
+	// Tools shared by several languages make the index a shared resource
+	l, err := lock.Acquire(m.Config.LockPath("shims"))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = l.Release() }()
+
+	index, err := loadShimIndex()
+	if err != nil {
+		return err
+	}
+
+	for _, relDir := range pathDirs {
+		binDir := joinRel(versionPath, relDir)
+		entries, err := os.ReadDir(binDir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			tool, ok := toolName(binDir, entry)
+			if !ok || strings.EqualFold(tool, "verman") {
+				continue
+			}
+			if err := linkShim(source, shimDir, tool); err != nil {
+				continue // Skip on error, non-fatal
+			}
+			index[shimKey(tool)] = langName
+		}
+	}
+
+	return saveShimIndex(index)
+}
+
+// Reshim recreates the shims for every installed version, e.g. after the
+// verman executable was updated or moved
+func (m *Manager) Reshim() error {
+	for _, lang := range languages.All() {
+		versions, err := m.ListInstalled(lang.Name())
+		if err != nil {
+			return err
+		}
+		// Oldest first, so the newest version's tools are linked last
+		semver.Sort(versions)
+		for i := len(versions) - 1; i >= 0; i-- {
+			if err := m.CreateShims(lang.Name(), m.Config.GetVersionPath(lang.Name(), versions[i]), lang.PathDirs()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Executable returns the verman executable shims and shell integration
+// point at: the installed ~/.verman/bin/verman if present, else the running
+// binary
+func Executable() (string, error) {
+	shimDir, err := ShimDir()
+	if err != nil {
+		return "", err
+	}
+	installed := filepath.Join(shimDir, "verman"+exeSuffix)
+	if _, err := os.Stat(installed); err == nil {
+		return installed, nil
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(exe)
+}
+
+// ShimCommand resolves what a shim runs in dir: the real tool's path and
+// the environment to run it with. The tool comes from the session, project
+// or global version of its language (see Select), and dependencies (Java
+// for Maven) are selected the same way.
+func (m *Manager) ShimCommand(name, dir string, environ []string) (string, []string, error) {
+	langName, ok := ShimLanguage(name)
+	if !ok {
+		return "", nil, fmt.Errorf("%s is not a verman shim; run: verman reshim", name)
+	}
+	lang, ok := languages.Get(langName)
+	if !ok {
+		return "", nil, fmt.Errorf("unknown language: %s", langName)
+	}
+
+	sel, err := m.Select(langName, dir)
+	if err != nil {
+		return "", nil, err
+	}
+	if sel == nil {
+		return "", nil, fmt.Errorf("no %s version set for %s; run: verman use %s <version>", langName, name, langName)
+	}
+
+	var dirs []string
+	for _, relDir := range lang.PathDirs() {
+		dirs = append(dirs, joinRel(sel.Path, relDir))
+	}
+	command, ok := findTool(dirs, name)
+	if !ok {
+		return "", nil, fmt.Errorf("%s not found in %s %s (%s)", name, langName, sel.Version, sel.Origin)
+	}
+
+	tools := []ToolVersion{{Language: langName, Version: sel.Version}}
+	for _, dep := range lang.GetDependencies() {
+		if depSel, err := m.Select(dep, dir); err == nil && depSel != nil {
+			tools = append(tools, ToolVersion{Language: dep, Version: depSel.Version})
+		}
+	}
+	env, err := m.ExecEnv(tools, environ)
+	if err != nil {
+		return "", nil, err
+	}
+	return command, env, nil
+}
+
+// findTool looks for an executable named name in dirs
+func findTool(dirs []string, name string) (string, bool) {
+	for _, dir := range dirs {
+		for _, ext := range toolExts {
+			path := filepath.Join(dir, name+ext)
+			if info, err := os.Stat(path); err == nil && isExecutable(info) {
+				return path, true
+			}
+		}
+	}
+	return "", false
+}
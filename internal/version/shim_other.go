@@ -0,0 +1,54 @@
+//go:build !windows
+
+package version
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const exeSuffix = ""
+
+// toolExts are the extensions a shimmed tool may have
+var toolExts = []string{""}
+
+func shimKey(name string) string {
+	return name
+}
+
+// toolName returns the command name an entry is run as, if it is an
+// executable file (or a link to one)
+func toolName(dir string, entry os.DirEntry) (string, bool) {
+	info, err := entry.Info()
+	if err != nil {
+		return "", false
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		// Follow links such as bin/npm -> ../lib/node_modules/npm/bin/npm-cli.js
+		if info, err = os.Stat(filepath.Join(dir, entry.Name())); err != nil {
+			return "", false
+		}
+	}
+	if info.IsDir() || !isExecutable(info) {
+		return "", false
+	}
+	return entry.Name(), true
+}
+
+func isExecutable(info os.FileInfo) bool {
+	return !info.IsDir() && info.Mode()&0111 != 0
+}
+
+// linkShim symlinks <tool> to the verman executable
+func linkShim(source, shimDir, tool string) error {
+	shimPath := filepath.Join(shimDir, tool)
+	if target, err := os.Readlink(shimPath); err == nil && target == source {
+		return nil
+	}
+	tmpPath := shimPath + ".tmp"
+	_ = os.Remove(tmpPath)
+	if err := os.Symlink(source, tmpPath); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, shimPath)
+}
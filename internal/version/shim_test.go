@@ -0,0 +1,94 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// createMockTool adds an executable to a mock version's bin directory
+func createMockTool(t *testing.T, versionDir, name string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	if err := os.WriteFile(filepath.Join(versionDir, "bin", name), []byte("mock tool"), 0755); err != nil {
+		t.Fatalf("Failed to create mock tool: %v", err)
+	}
+}
+
+func TestCreateShims(t *testing.T) {
+	mgr, home := setupTestManager(t)
+	java17 := createMockVersion(t, mgr, "java", "17.0.9")
+	createMockTool(t, java17, "java")
+	createMockTool(t, java17, "javac")
+	_ = os.WriteFile(filepath.Join(java17, "bin", "README"), []byte("not a tool"), 0644)
+
+	if err := mgr.CreateShims("java", java17, []string{"bin"}); err != nil {
+		t.Fatalf("CreateShims failed: %v", err)
+	}
+
+	for _, tool := range []string{"java", "javac"} {
+		if langName, ok := ShimLanguage(tool); !ok || langName != "java" {
+			t.Errorf("ShimLanguage(%s) = %s, %v; want java", tool, langName, ok)
+		}
+		if _, err := os.Stat(filepath.Join(home, ".verman", "bin", tool+exeSuffix)); err != nil {
+			t.Errorf("Missing shim for %s: %v", tool, err)
+		}
+	}
+	if _, ok := ShimLanguage("README"); ok {
+		t.Error("Non-executables should not be shimmed")
+	}
+}
+
+func TestShimCommand(t *testing.T) {
+	mgr, tmpDir := setupTestManager(t)
+	java17 := createMockVersion(t, mgr, "java", "17.0.9")
+	java21 := createMockVersion(t, mgr, "java", "21.0.2")
+	createMockTool(t, java17, "java")
+	createMockTool(t, java21, "java")
+	if err := switchJunction(mgr.Config.GetCurrentPath("java"), java21); err != nil {
+		t.Fatalf("switchJunction failed: %v", err)
+	}
+	if err := mgr.CreateShims("java", java21, []string{"bin"}); err != nil {
+		t.Fatalf("CreateShims failed: %v", err)
+	}
+	t.Setenv(SessionVar("java"), "")
+
+	project := filepath.Join(tmpDir, "project")
+	_ = os.MkdirAll(project, 0755)
+	_ = os.WriteFile(filepath.Join(project, ".java-version"), []byte("17\n"), 0644)
+
+	// The global version runs through the "current" link
+	current := mgr.Config.GetCurrentPath("java")
+	tests := []struct {
+		dir     string
+		session string
+		root    string
+		want    string
+	}{
+		{tmpDir, "", current, java21},
+		{project, "", java17, java17},
+		{tmpDir, "17", java17, java17},
+		{project, "21", java21, java21},
+	}
+	for _, tt := range tests {
+		t.Setenv(SessionVar("java"), tt.session)
+		command, env, err := mgr.ShimCommand("java", tt.dir, []string{"PATH=/usr/bin"})
+		if err != nil {
+			t.Errorf("ShimCommand(%s, session %q) failed: %v", tt.dir, tt.session, err)
+			continue
+		}
+		if filepath.Dir(filepath.Dir(command)) != tt.root {
+			t.Errorf("ShimCommand(%s, session %q) = %s, want a tool in %s", tt.dir, tt.session, command, tt.root)
+		}
+		if got := getEnv(env, "JAVA_HOME"); got != tt.want {
+			t.Errorf("JAVA_HOME = %s, want %s", got, tt.want)
+		}
+	}
+
+	if _, _, err := mgr.ShimCommand("mvn", tmpDir, nil); err == nil {
+		t.Error("Expected error for a tool without a shim")
+	}
+}
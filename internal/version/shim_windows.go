@@ -0,0 +1,90 @@
+//go:build windows
+
+package version
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const exeSuffix = ".exe"
+
+// toolExts are the extensions a shimmed tool may have, in PATHEXT order
+var toolExts = []string{".exe", ".com", ".bat", ".cmd"}
+
+// shimKey normalizes a shim name: case-insensitive, without ".exe"
+func shimKey(name string) string {
+	name = strings.ToLower(name)
+	return strings.TrimSuffix(name, exeSuffix)
+}
+
+// toolName returns the command name an entry is run as ("mvn" for
+// mvn.cmd), if it is an executable
+func toolName(_ string, entry os.DirEntry) (string, bool) {
+	name := entry.Name()
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, e := range toolExts {
+		if ext == e && !entry.IsDir() {
+			return strings.TrimSuffix(name, filepath.Ext(name)), true
+		}
+	}
+	return "", false
+}
+
+func isExecutable(info os.FileInfo) bool {
+	return !info.IsDir()
+}
+
+// linkShim hard links (or, across volumes, copies) the verman executable to
+// <tool>.exe. Batch shims from older versions are removed so they do not
+// shadow it.
+func linkShim(source, shimDir, tool string) error {
+	shimPath := filepath.Join(shimDir, tool+exeSuffix)
+	removeBatchShim(filepath.Join(shimDir, tool+".cmd"))
+
+	if info, err := os.Stat(shimPath); err == nil {
+		if srcInfo, err := os.Stat(source); err == nil && os.SameFile(info, srcInfo) {
+			return nil
+		}
+		if err := os.Remove(shimPath); err != nil {
+			// A running shim cannot be deleted, but it can be renamed away
+			if err := os.Rename(shimPath, shimPath+".old"); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := os.Link(source, shimPath); err == nil {
+		return nil
+	}
+	return copyExecutable(source, shimPath)
+}
+
+// removeBatchShim deletes a .cmd shim written by earlier verman versions
+func removeBatchShim(path string) {
+	data, err := os.ReadFile(path)
+	if err == nil && bytes.HasPrefix(data, []byte("@echo off\r\n")) && bytes.Contains(data, []byte("%*")) {
+		_ = os.Remove(path)
+	}
+}
+
+func copyExecutable(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,108 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// stagingDirName is the folder under Config.RootPath where installs are
+// assembled before being renamed into place. Being on the same volume as the
+// version directories under RootPath keeps the rename atomic.
+const stagingDirName = ".staging"
+
+// staleStagingAge is how old a staging directory without a recognizable
+// owner must be before the sweep removes it
+const staleStagingAge = 24 * time.Hour
+
+// stagingRoot returns the directory holding in-progress installs
+func (m *Manager) stagingRoot() string {
+	return filepath.Join(m.Config.RootPath, stagingDirName)
+}
+
+// newStagingDir creates an empty staging directory for an install.
+// The name starts with the owning PID so the sweep can tell live installs
+// from abandoned ones: "<pid>-<lang>-<version>-<random>".
+func (m *Manager) newStagingDir(langName, versionKey string) (string, error) {
+	root := m.stagingRoot()
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return "", err
+	}
+	return os.MkdirTemp(root, fmt.Sprintf("%d-%s-%s-*", os.Getpid(), langName, versionKey))
+}
+
+// SweepStaging removes staging directories and partial downloads left behind
+// by installs that crashed or were killed. Directories owned by a running process are kept.
+// Returns the number of directories removed.
+func (m *Manager) SweepStaging() (int, error) {
+	entries, err := os.ReadDir(m.stagingRoot())
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if !m.isAbandonedStaging(entry) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(m.stagingRoot(), entry.Name())); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+
+	// The staging root itself stays: removing it could race with another
+	// process's newStagingDir between its MkdirAll and MkdirTemp
+	return removed, nil
+}
+
+// isAbandonedStaging reports whether a staging entry belongs to no live install
+func (m *Manager) isAbandonedStaging(entry os.DirEntry) bool {
+	pidStr, _, _ := strings.Cut(entry.Name(), "-")
+	if pid, err := strconv.Atoi(pidStr); err == nil && pid > 0 {
+		return pid != os.Getpid() && !processAlive(pid)
+	}
+
+	// Unknown naming: only remove once clearly stale
+	info, err := entry.Info()
+	return err == nil && time.Since(info.ModTime()) > staleStagingAge
+}
+
+// commitStaging moves a fully prepared staging directory to its final path
+func commitStaging(stagingPath, versionPath string) error {
+	entries, err := os.ReadDir(stagingPath)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("install produced no files")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(versionPath), 0755); err != nil {
+		return err
+	}
+	if _, err := os.Lstat(versionPath); err == nil {
+		return fmt.Errorf("%s already exists", versionPath)
+	}
+
+	// On Windows, virus scanners and the search indexer briefly hold handles
+	// on freshly extracted files, which makes the rename fail with access denied
+	attempts := 1
+	if runtime.GOOS == "windows" {
+		attempts = 10
+	}
+	for i := 0; ; i++ {
+		err = os.Rename(stagingPath, versionPath)
+		if err == nil || i+1 >= attempts {
+			return err
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
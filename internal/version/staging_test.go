@@ -0,0 +1,112 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// deadPID returns the PID of a process that has already exited
+func deadPID(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to run helper process: %v", err)
+	}
+	return cmd.Process.Pid
+}
+
+func TestSweepStaging(t *testing.T) {
+	mgr, _ := setupTestManager(t)
+	root := mgr.stagingRoot()
+
+	live, err := mgr.newStagingDir("java", "21.0.2-tem")
+	if err != nil {
+		t.Fatalf("newStagingDir failed: %v", err)
+	}
+
+	abandoned := filepath.Join(root, fmt.Sprintf("%d-node-20.11.0-123", deadPID(t)))
+	abandonedArchive := filepath.Join(root, fmt.Sprintf("%d-go-1.22.0-456.archive", deadPID(t)))
+	unknownFresh := filepath.Join(root, "leftover")
+	unknownStale := filepath.Join(root, "ancient")
+	for _, dir := range []string{abandoned, unknownFresh, unknownStale} {
+		if err := os.MkdirAll(filepath.Join(dir, "bin"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = os.WriteFile(abandonedArchive, []byte("partial"), 0644)
+	old := time.Now().Add(-2 * staleStagingAge)
+	_ = os.Chtimes(unknownStale, old, old)
+
+	removed, err := mgr.SweepStaging()
+	if err != nil {
+		t.Fatalf("SweepStaging failed: %v", err)
+	}
+	if removed != 3 {
+		t.Errorf("Expected 3 entries removed, got %d", removed)
+	}
+
+	for _, p := range []string{abandoned, abandonedArchive, unknownStale} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be swept", filepath.Base(p))
+		}
+	}
+	for _, p := range []string{live, unknownFresh, root} {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("Expected %s to be kept: %v", filepath.Base(p), err)
+		}
+	}
+}
+
+func TestSweepStagingNoDirectory(t *testing.T) {
+	mgr, _ := setupTestManager(t)
+
+	removed, err := mgr.SweepStaging()
+	if err != nil || removed != 0 {
+		t.Errorf("Expected no-op sweep, got %d, %v", removed, err)
+	}
+}
+
+func TestCommitStaging(t *testing.T) {
+	mgr, _ := setupTestManager(t)
+
+	staging, err := mgr.newStagingDir("java", "21")
+	if err != nil {
+		t.Fatalf("newStagingDir failed: %v", err)
+	}
+	versionPath := mgr.Config.GetVersionPath("java", "21")
+
+	// An empty staging directory is never committed
+	if err := commitStaging(staging, versionPath); err == nil {
+		t.Fatal("Expected error committing an empty install")
+	}
+	if _, err := os.Stat(versionPath); !os.IsNotExist(err) {
+		t.Fatal("Version directory must not exist after a failed commit")
+	}
+
+	_ = os.MkdirAll(filepath.Join(staging, "bin"), 0755)
+	if err := commitStaging(staging, versionPath); err != nil {
+		t.Fatalf("commitStaging failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(versionPath, "bin")); err != nil {
+		t.Errorf("Expected committed install: %v", err)
+	}
+	if _, err := os.Stat(staging); !os.IsNotExist(err) {
+		t.Error("Expected staging directory to be moved away")
+	}
+
+	installed, _ := mgr.ListInstalled("java")
+	if len(installed) != 1 || installed[0] != "21" {
+		t.Errorf("Expected only the committed version to be listed, got %v", installed)
+	}
+
+	// An existing version is never overwritten
+	other, _ := mgr.newStagingDir("java", "21")
+	_ = os.WriteFile(filepath.Join(other, "release"), []byte("x"), 0644)
+	if err := commitStaging(other, versionPath); err == nil {
+		t.Error("Expected error when the version already exists")
+	}
+}